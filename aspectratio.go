@@ -42,3 +42,20 @@ func (this *AspectRatio) NewDimensions(v float64) Dimensions {
 		}
 	}
 }
+
+// Fit returns the largest dimensions with this aspect ratio that fit within
+// the given dimensions.
+func (this *AspectRatio) Fit(d Dimensions) Dimensions {
+	width := Dimensions{
+		Width:  d.Width,
+		Height: d.Width / this.Width * this.Height,
+	}
+	if width.Height <= d.Height {
+		return width
+	}
+
+	return Dimensions{
+		Width:  d.Height / this.Height * this.Width,
+		Height: d.Height,
+	}
+}
@@ -0,0 +1,359 @@
+package go2d
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/png" // Atlas exports are almost always PNG images.
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultAtlasFrameDuration is the duration of frames that do not specify one,
+// which matches the default frame duration of Aseprite.
+const defaultAtlasFrameDuration = 100 * time.Millisecond
+
+// AtlasTag is a named range of frames in a texture atlas, as exported by
+// Aseprite.
+type AtlasTag struct {
+	// Name is the name of the tag.
+	Name string
+	// From is the index of the first frame of the tag.
+	From int
+	// To is the index of the last frame of the tag.
+	To int
+	// Direction is the direction the frames of the tag are played in. It is
+	// one of "forward", "reverse" or "pingpong".
+	Direction string
+}
+
+// TextureAtlas is a collection of named images packed into a single texture,
+// as exported by TexturePacker or Aseprite. Trimmed and rotated frames are
+// restored to their original size and orientation when the atlas is loaded.
+type TextureAtlas struct {
+	names     []string
+	frames    map[string]image.Image
+	durations map[string]time.Duration
+	tags      []AtlasTag
+}
+
+type atlasRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type atlasFrame struct {
+	Filename         string    `json:"filename"`
+	Frame            atlasRect `json:"frame"`
+	Rotated          bool      `json:"rotated"`
+	Trimmed          bool      `json:"trimmed"`
+	SpriteSourceSize atlasRect `json:"spriteSourceSize"`
+	SourceSize       atlasRect `json:"sourceSize"`
+	Duration         int       `json:"duration"`
+}
+
+type atlasFile struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		Image     string `json:"image"`
+		FrameTags []struct {
+			Name      string `json:"name"`
+			From      int    `json:"from"`
+			To        int    `json:"to"`
+			Direction string `json:"direction"`
+		} `json:"frameTags"`
+	} `json:"meta"`
+}
+
+// LoadTextureAtlas loads a texture atlas from the JSON file at the given path.
+// Both the hash and array formats of TexturePacker and Aseprite are supported.
+// The texture is loaded from the image named in the meta data of the file,
+// relative to the file.
+func LoadTextureAtlas(path string) (*TextureAtlas, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file atlasFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	imgf, err := os.Open(filepath.Join(filepath.Dir(path), file.Meta.Image))
+	if err != nil {
+		return nil, err
+	}
+	defer imgf.Close()
+
+	img, _, err := image.Decode(imgf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTextureAtlas(data, img)
+}
+
+// NewTextureAtlas creates a texture atlas from the given JSON data describing
+// the frames in the given texture.
+func NewTextureAtlas(data []byte, texture image.Image) (*TextureAtlas, error) {
+	var file atlasFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	frames, err := parseAtlasFrames(file.Frames)
+	if err != nil {
+		return nil, err
+	}
+
+	atlas := &TextureAtlas{
+		names:     []string{},
+		frames:    map[string]image.Image{},
+		durations: map[string]time.Duration{},
+		tags:      []AtlasTag{},
+	}
+
+	for _, frame := range frames {
+		img, err := frame.extract(texture)
+		if err != nil {
+			return nil, err
+		}
+
+		atlas.names = append(atlas.names, frame.Filename)
+		atlas.frames[frame.Filename] = img
+		atlas.durations[frame.Filename] = time.Duration(frame.Duration) * time.Millisecond
+	}
+
+	for _, tag := range file.Meta.FrameTags {
+		if tag.From < 0 || tag.To >= len(atlas.names) || tag.From > tag.To {
+			return nil, fmt.Errorf("frame tag %q is out of range", tag.Name)
+		}
+
+		atlas.tags = append(atlas.tags, AtlasTag{
+			Name:      tag.Name,
+			From:      tag.From,
+			To:        tag.To,
+			Direction: tag.Direction,
+		})
+	}
+
+	return atlas, nil
+}
+
+// parseAtlasFrames parses the frames of an atlas in either the array or the
+// hash format. The order of the frames is kept for the hash format as well,
+// since frame tags refer to frames by their index.
+func parseAtlasFrames(data json.RawMessage) ([]atlasFrame, error) {
+	frames := []atlasFrame{}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return frames, nil
+	}
+
+	if data[0] == '[' {
+		err := json.Unmarshal(data, &frames)
+		return frames, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var frame atlasFrame
+		if err := decoder.Decode(&frame); err != nil {
+			return nil, err
+		}
+		frame.Filename = key.(string)
+		frames = append(frames, frame)
+	}
+
+	return frames, nil
+}
+
+// extract returns the image of the frame from the given texture, restoring its
+// original size and orientation.
+func (this atlasFrame) extract(texture image.Image) (image.Image, error) {
+	subImager, ok := texture.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, ErrSubImageUnsupported
+	}
+
+	// Rotated frames are stored rotated 90 degrees clockwise, so they take up
+	// the width and height of the frame the other way around.
+	w, h := this.Frame.W, this.Frame.H
+	if this.Rotated {
+		w, h = h, w
+	}
+
+	origin := texture.Bounds().Min
+	region := subImager.SubImage(image.Rect(
+		origin.X+this.Frame.X, origin.Y+this.Frame.Y,
+		origin.X+this.Frame.X+w, origin.Y+this.Frame.Y+h,
+	))
+
+	if !this.Rotated && !this.Trimmed {
+		return region, nil
+	}
+
+	var sprite image.Image = region
+	if this.Rotated {
+		rotated := image.NewRGBA(image.Rect(0, 0, this.Frame.W, this.Frame.H))
+		bounds := region.Bounds()
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				rotated.Set(y, this.Frame.H-1-x, region.At(bounds.Min.X+x, bounds.Min.Y+y))
+			}
+		}
+		sprite = rotated
+	}
+
+	if !this.Trimmed {
+		return sprite, nil
+	}
+
+	source := image.NewRGBA(image.Rect(0, 0, this.SourceSize.W, this.SourceSize.H))
+	draw.Draw(
+		source,
+		image.Rect(
+			this.SpriteSourceSize.X, this.SpriteSourceSize.Y,
+			this.SpriteSourceSize.X+this.Frame.W, this.SpriteSourceSize.Y+this.Frame.H,
+		),
+		sprite,
+		sprite.Bounds().Min,
+		draw.Src,
+	)
+
+	return source, nil
+}
+
+// GetNames returns the names of all frames in the atlas in the order they
+// appear in the atlas.
+func (this *TextureAtlas) GetNames() []string {
+	return append([]string{}, this.names...)
+}
+
+// GetImage returns the frame with the given name, or nil if the atlas does not
+// contain it.
+func (this *TextureAtlas) GetImage(name string) image.Image {
+	return this.frames[name]
+}
+
+// GetImageAt returns the frame at the given index, or nil if the index is out
+// of range.
+func (this *TextureAtlas) GetImageAt(index int) image.Image {
+	if index < 0 || index >= len(this.names) {
+		return nil
+	}
+
+	return this.frames[this.names[index]]
+}
+
+// GetDuration returns the duration of the frame with the given name. Frames
+// without a duration return 0.
+func (this *TextureAtlas) GetDuration(name string) time.Duration {
+	return this.durations[name]
+}
+
+// GetTags returns the frame tags of the atlas.
+func (this *TextureAtlas) GetTags() []AtlasTag {
+	return append([]AtlasTag{}, this.tags...)
+}
+
+// GetTag returns the frame tag with the given name.
+func (this *TextureAtlas) GetTag(name string) (AtlasTag, bool) {
+	for _, tag := range this.tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+
+	return AtlasTag{}, false
+}
+
+// NewImageEntity creates a new image entity from the frame with the given name.
+func (this *TextureAtlas) NewImageEntity(name string) (*ImageEntity, error) {
+	img := this.GetImage(name)
+	if img == nil {
+		return nil, fmt.Errorf("%w: %q", ErrAtlasFrameNotFound, name)
+	}
+
+	return NewImageEntity(img), nil
+}
+
+// NewAnimationClip creates a new animation clip from the frames with the given
+// names. Each frame is shown for its own duration from the atlas, or for the
+// given frame duration if it does not have one.
+func (this *TextureAtlas) NewAnimationClip(names []string, frameDuration time.Duration, mode AnimationMode) (*AnimationClip, error) {
+	clip := NewAnimationClip([]image.Image{}, frameDuration, mode)
+	clip.Durations = []time.Duration{}
+
+	for _, name := range names {
+		img := this.GetImage(name)
+		if img == nil {
+			return nil, fmt.Errorf("%w: %q", ErrAtlasFrameNotFound, name)
+		}
+
+		duration := this.GetDuration(name)
+		if duration <= 0 {
+			duration = frameDuration
+		}
+
+		clip.Frames = append(clip.Frames, img)
+		clip.Durations = append(clip.Durations, duration)
+	}
+
+	return clip, nil
+}
+
+// NewTagAnimationClip creates a new animation clip from the frames of the tag
+// with the given name, using the frame durations and direction of the tag.
+func (this *TextureAtlas) NewTagAnimationClip(tag string) (*AnimationClip, error) {
+	t, ok := this.GetTag(tag)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrAtlasTagNotFound, tag)
+	}
+
+	names := append([]string{}, this.names[t.From:t.To+1]...)
+	var mode AnimationMode = AnimationModeLoop
+	switch t.Direction {
+	case "pingpong":
+		mode = AnimationModePingPong
+	case "reverse":
+		for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+			names[i], names[j] = names[j], names[i]
+		}
+	}
+
+	return this.NewAnimationClip(names, defaultAtlasFrameDuration, mode)
+}
+
+// NewAnimatedSpriteEntity creates a new animated sprite entity with a clip for
+// each tag in the atlas.
+func (this *TextureAtlas) NewAnimatedSpriteEntity() (*AnimatedSpriteEntity, error) {
+	entity := NewAnimatedSpriteEntity()
+	for _, tag := range this.tags {
+		clip, err := this.NewTagAnimationClip(tag.Name)
+		if err != nil {
+			return nil, err
+		}
+		entity.AddClip(tag.Name, clip)
+	}
+
+	return entity, nil
+}
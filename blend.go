@@ -0,0 +1,140 @@
+package go2d
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// BlendMode is a type that represents the different ways an entity can be
+// blended with what has already been drawn behind it.
+type BlendMode int
+
+const (
+	// BlendModeNormal draws the entity over what is behind it.
+	BlendModeNormal BlendMode = iota
+	// BlendModeAdditive adds the colors of the entity to the colors behind
+	// it, which brightens them. This is useful for glows and lights.
+	BlendModeAdditive
+	// BlendModeMultiply multiplies the colors behind the entity with the
+	// colors of the entity, which darkens them. This is useful for shadows.
+	BlendModeMultiply
+	// BlendModeScreen multiplies the inverse of the colors behind the entity
+	// with the inverse of the colors of the entity, which brightens them
+	// without overexposing them as much as BlendModeAdditive.
+	BlendModeScreen
+)
+
+// blend calls draw to draw an entity onto the canvas of the engine using the
+// given blend mode. Draw must only draw within the given area of the canvas,
+// in pixels.
+//
+// The canvas does not support blend modes, so the entity is drawn once over a
+// black backdrop and once over a white backdrop. The colors and coverage of
+// the entity are recovered from the difference between the two, even on
+// backends that can not read back the alpha channel, and then composited with
+// what was on the canvas before in software.
+func blend(e *Engine, mode BlendMode, area Rect, draw func()) {
+	// The area is grown by a pixel to include the edges of the entity that
+	// are partially covered.
+	region := image.Rect(
+		int(math.Floor(area.X))-1, int(math.Floor(area.Y))-1,
+		int(math.Ceil(area.X+area.Width))+1, int(math.Ceil(area.Y+area.Height))+1,
+	).Intersect(image.Rect(0, 0, e.Canvas.Width(), e.Canvas.Height()))
+	if region.Empty() {
+		return
+	}
+
+	dst := readCanvas(e, region)
+
+	drawOver := func(backdrop string) *image.RGBA {
+		e.Canvas.Save()
+		e.Canvas.SetTransform(1, 0, 0, 1, 0, 0)
+		e.Canvas.SetGlobalAlpha(1)
+		e.Canvas.SetFillStyle(backdrop)
+		e.Canvas.FillRect(float64(region.Min.X), float64(region.Min.Y), float64(region.Dx()), float64(region.Dy()))
+		e.Canvas.Restore()
+
+		draw()
+
+		return readCanvas(e, region)
+	}
+	overBlack := drawOver("#000")
+	overWhite := drawOver("#fff")
+
+	for i := 0; i+3 < len(dst.Pix); i += 4 {
+		// Over black each channel is the color of the entity multiplied by
+		// its coverage, and over white the uncovered part is added to it.
+		uncovered := 0
+		for c := 0; c < 3; c++ {
+			uncovered += int(overWhite.Pix[i+c]) - int(overBlack.Pix[i+c])
+		}
+		coverage := clampChannel(255 - uncovered/3)
+		if coverage == 0 {
+			continue
+		}
+
+		for c := 0; c < 3; c++ {
+			d := int(dst.Pix[i+c])
+			s := int(overBlack.Pix[i+c])
+
+			switch mode {
+			case BlendModeAdditive:
+				d = d + s
+			case BlendModeMultiply:
+				d = d*(255-coverage)/255 + d*s/255
+			case BlendModeScreen:
+				d = d + s - d*s/255
+			default:
+				d = d*(255-coverage)/255 + s
+			}
+
+			dst.Pix[i+c] = uint8(clampChannel(d))
+		}
+
+		a := int(dst.Pix[i+3])
+		dst.Pix[i+3] = uint8(clampChannel(a + coverage*(255-a)/255))
+	}
+
+	writeCanvas(e, dst, region.Min)
+}
+
+// readCanvas returns a copy of the given region of the canvas of the engine,
+// starting at 0, 0. The software canvas of headless engines is read directly,
+// and other canvases are read from the top, since their backends only read
+// back regions that start at the top of the canvas correctly.
+func readCanvas(e *Engine, region image.Rectangle) *image.RGBA {
+	var src *image.RGBA
+	if e.headlessBackend != nil {
+		src = e.headlessBackend.Image
+	} else {
+		src = e.Canvas.GetImageData(region.Min.X, 0, region.Dx(), region.Max.Y)
+	}
+
+	copied := image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy()))
+	draw.Draw(copied, copied.Bounds(), src, region.Min, draw.Src)
+
+	return copied
+}
+
+// writeCanvas puts an image read with readCanvas back onto the canvas of the
+// engine at the given position.
+func writeCanvas(e *Engine, img *image.RGBA, at image.Point) {
+	if e.headlessBackend != nil {
+		draw.Draw(e.headlessBackend.Image, img.Bounds().Add(at), img, image.Point{}, draw.Src)
+		return
+	}
+
+	e.Canvas.PutImageData(img, at.X, at.Y)
+}
+
+func clampChannel(c int) int {
+	if c < 0 {
+		return 0
+	}
+	if c > 255 {
+		return 255
+	}
+
+	return c
+}
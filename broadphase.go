@@ -0,0 +1,270 @@
+package go2d
+
+import (
+	"math"
+)
+
+// IBroadPhase is an interface that can be implemented by spatial structures
+// that quickly find the colliders that are near an area, so that the scene
+// only has to test colliders that can actually touch each other.
+type IBroadPhase interface {
+	// Clear removes all items.
+	Clear()
+	// Insert adds an item that occupies the given bounds.
+	Insert(item interface{}, bounds Rect)
+	// Query calls cb once for every item whose bounds may intersect the given
+	// bounds. It may also call cb for items that do not intersect them.
+	Query(bounds Rect, cb func(item interface{}))
+}
+
+// spatialHashMaxCells is the largest number of cells that the bounds of an
+// item or a query may cover in a spatial hash before it is treated as
+// covering the whole world instead.
+const spatialHashMaxCells = 1024
+
+// SpatialHash is a broad phase that divides the world into a uniform grid of
+// cells. It works best when most colliders are about the size of a cell.
+// Items with bounds that cover too many cells, or that are not finite, are
+// kept apart and returned by every query.
+type SpatialHash struct {
+	// CellSize is the width and height of each cell.
+	CellSize float64
+
+	cells map[[2]int][]int
+	large []int
+	items []spatialHashItem
+	query int
+}
+
+type spatialHashItem struct {
+	item   interface{}
+	bounds Rect
+	mark   int
+}
+
+// NewSpatialHash creates a new spatial hash with cells of the given size.
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	return &SpatialHash{
+		CellSize: cellSize,
+		cells:    map[[2]int][]int{},
+		items:    []spatialHashItem{},
+	}
+}
+
+// Clear removes all items from the spatial hash. Cells that were used since
+// the last time it was cleared are kept so that they can be refilled without
+// allocating, while the cells that were not are deleted, so that the spatial
+// hash does not keep growing as colliders move around the world.
+func (this *SpatialHash) Clear() {
+	for key, cell := range this.cells {
+		if len(cell) == 0 {
+			delete(this.cells, key)
+		} else {
+			this.cells[key] = cell[:0]
+		}
+	}
+	this.large = this.large[:0]
+	this.items = this.items[:0]
+}
+
+// Insert adds an item to every cell that the given bounds overlap.
+func (this *SpatialHash) Insert(item interface{}, bounds Rect) {
+	index := len(this.items)
+	this.items = append(this.items, spatialHashItem{
+		item:   item,
+		bounds: bounds,
+		mark:   this.query,
+	})
+
+	if !this.eachCell(bounds, func(key [2]int) {
+		this.cells[key] = append(this.cells[key], index)
+	}) {
+		this.large = append(this.large, index)
+	}
+}
+
+// Query calls cb once for every item that shares a cell with the given bounds.
+func (this *SpatialHash) Query(bounds Rect, cb func(item interface{})) {
+	this.query += 1
+	query := this.query
+
+	visit := func(index int) {
+		if this.items[index].mark != query {
+			this.items[index].mark = query
+			cb(this.items[index].item)
+		}
+	}
+
+	if !this.eachCell(bounds, func(key [2]int) {
+		for _, index := range this.cells[key] {
+			visit(index)
+		}
+	}) {
+		for index := range this.items {
+			visit(index)
+		}
+		return
+	}
+
+	for _, index := range this.large {
+		visit(index)
+	}
+}
+
+// eachCell calls cb for every cell that the given bounds overlap. If the
+// bounds cover more than spatialHashMaxCells cells or are not finite, cb is
+// not called and false is returned.
+func (this *SpatialHash) eachCell(bounds Rect, cb func(key [2]int)) bool {
+	cellSize := this.CellSize
+	if cellSize <= 0 {
+		cellSize = 64
+	}
+
+	minX := math.Floor(bounds.X / cellSize)
+	minY := math.Floor(bounds.Y / cellSize)
+	maxX := math.Floor((bounds.X + bounds.Width) / cellSize)
+	maxY := math.Floor((bounds.Y + bounds.Height) / cellSize)
+
+	// The comparisons are false for NaN, so bounds that are not a number are
+	// treated as too large as well. Cells far enough away to overflow an int
+	// are treated the same way.
+	if !((maxX-minX+1)*(maxY-minY+1) <= spatialHashMaxCells) ||
+		!(math.Max(math.Abs(minX), math.Abs(maxY)) < 1<<40 && math.Max(math.Abs(maxX), math.Abs(minY)) < 1<<40) {
+		return false
+	}
+
+	for x := int(minX); x <= int(maxX); x++ {
+		for y := int(minY); y <= int(maxY); y++ {
+			cb([2]int{x, y})
+		}
+	}
+
+	return true
+}
+
+// QuadTree is a broad phase that recursively divides an area into quarters
+// wherever there are many colliders. It works well when colliders vary a lot
+// in size or are unevenly spread out. Items outside of the bounds of the tree
+// are still found, but are not divided.
+type QuadTree struct {
+	// Bounds is the area that the tree divides.
+	Bounds Rect
+	// MaxItems is the number of items a node holds before it is divided.
+	MaxItems int
+	// MaxDepth is the maximum number of times a node can be divided.
+	MaxDepth int
+
+	root *quadTreeNode
+}
+
+type quadTreeItem struct {
+	item   interface{}
+	bounds Rect
+}
+
+type quadTreeNode struct {
+	bounds   Rect
+	depth    int
+	items    []quadTreeItem
+	children []*quadTreeNode
+}
+
+// NewQuadTree creates a new quad tree covering the given bounds.
+func NewQuadTree(bounds Rect, maxItems int, maxDepth int) *QuadTree {
+	tree := &QuadTree{
+		Bounds:   bounds,
+		MaxItems: maxItems,
+		MaxDepth: maxDepth,
+	}
+	tree.Clear()
+
+	return tree
+}
+
+// Clear removes all items from the quad tree.
+func (this *QuadTree) Clear() {
+	this.root = &quadTreeNode{
+		bounds: this.Bounds,
+	}
+}
+
+// Insert adds an item to the smallest node that fully contains the given
+// bounds.
+func (this *QuadTree) Insert(item interface{}, bounds Rect) {
+	this.insert(this.root, quadTreeItem{item: item, bounds: bounds})
+}
+
+// Query calls cb once for every item in the nodes that intersect the given
+// bounds.
+func (this *QuadTree) Query(bounds Rect, cb func(item interface{})) {
+	this.query(this.root, bounds, cb)
+}
+
+func (this *QuadTree) insert(node *quadTreeNode, item quadTreeItem) {
+	for node.children != nil {
+		child := node.childContaining(item.bounds)
+		if child == nil {
+			break
+		}
+		node = child
+	}
+
+	node.items = append(node.items, item)
+
+	if node.children == nil && len(node.items) > this.MaxItems && node.depth < this.MaxDepth {
+		node.split()
+
+		items := node.items
+		node.items = []quadTreeItem{}
+		for _, item := range items {
+			this.insert(node, item)
+		}
+	}
+}
+
+func (this *QuadTree) query(node *quadTreeNode, bounds Rect, cb func(item interface{})) {
+	for _, item := range node.items {
+		cb(item.item)
+	}
+
+	for _, child := range node.children {
+		if child.touches(bounds) {
+			this.query(child, bounds, cb)
+		}
+	}
+}
+
+func (this *quadTreeNode) split() {
+	w, h := this.bounds.Width/2, this.bounds.Height/2
+	x, y := this.bounds.X, this.bounds.Y
+
+	this.children = []*quadTreeNode{
+		{bounds: NewRect(x, y, w, h), depth: this.depth + 1},
+		{bounds: NewRect(x+w, y, w, h), depth: this.depth + 1},
+		{bounds: NewRect(x, y+h, w, h), depth: this.depth + 1},
+		{bounds: NewRect(x+w, y+h, w, h), depth: this.depth + 1},
+	}
+}
+
+// touches returns true if the node intersects or touches the given bounds,
+// which may have no width or height.
+func (this *quadTreeNode) touches(bounds Rect) bool {
+	return this.bounds.X <= bounds.X+bounds.Width &&
+		this.bounds.X+this.bounds.Width >= bounds.X &&
+		this.bounds.Y <= bounds.Y+bounds.Height &&
+		this.bounds.Y+this.bounds.Height >= bounds.Y
+}
+
+// childContaining returns the child node that fully contains the given bounds,
+// or nil if the bounds do not fit in a single child.
+func (this *quadTreeNode) childContaining(bounds Rect) *quadTreeNode {
+	for _, child := range this.children {
+		if bounds.X >= child.bounds.X && bounds.Y >= child.bounds.Y &&
+			bounds.X+bounds.Width <= child.bounds.X+child.bounds.Width &&
+			bounds.Y+bounds.Height <= child.bounds.Y+child.bounds.Height {
+			return child
+		}
+	}
+
+	return nil
+}
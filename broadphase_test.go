@@ -0,0 +1,168 @@
+package go2d
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+type testCollider struct {
+	Entity
+	name string
+	hits []string
+}
+
+func (this *testCollider) GetEntity() *Entity {
+	return &this.Entity
+}
+
+func (this *testCollider) GetCollider() Rect {
+	return this.Bounds
+}
+
+func (this *testCollider) CollidedWith(other interface{}) {
+	this.hits = append(this.hits, other.(interface{ colliderName() string }).colliderName())
+}
+
+func (this *testCollider) colliderName() string {
+	return this.name
+}
+
+// newCollisionScene creates a scene with n colliders placed at random in a
+// square world that is sized so that each collider overlaps a few others.
+func newCollisionScene(n int, broadPhase func(world Rect) IBroadPhase) (*Engine, *Scene, []*testCollider) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	scene := NewScene(engine, "collisions")
+
+	size := 16.0
+	world := NewRect(0, 0, size*float64(n)/2, size*float64(n)/2)
+	if world.Width < 256 {
+		world = NewRect(0, 0, 256, 256)
+	}
+	scene.BroadPhase = broadPhase(world)
+
+	r := rand.New(rand.NewSource(1))
+	colliders := []*testCollider{}
+	for i := 0; i < n; i++ {
+		c := &testCollider{name: fmt.Sprintf("c%v", i)}
+		c.Bounds = NewRect(r.Float64()*(world.Width-size), r.Float64()*(world.Height-size), size, size)
+		scene.AddNamedEntity(c.name, 0, c)
+		colliders = append(colliders, c)
+	}
+
+	return engine, &scene, colliders
+}
+
+var broadPhases = []struct {
+	name string
+	new  func(world Rect) IBroadPhase
+}{
+	{"None", func(world Rect) IBroadPhase { return nil }},
+	{"SpatialHash", func(world Rect) IBroadPhase { return NewSpatialHash(DefaultCollisionCellSize) }},
+	{"QuadTree", func(world Rect) IBroadPhase { return NewQuadTree(world, 8, 8) }},
+}
+
+func TestBroadPhasesFindTheSameCollisions(t *testing.T) {
+	var want map[string][]string
+	for _, phase := range broadPhases {
+		engine, scene, colliders := newCollisionScene(200, phase.new)
+		scene.performCollisions(engine)
+
+		got := map[string][]string{}
+		total := 0
+		for _, c := range colliders {
+			sort.Strings(c.hits)
+			got[c.name] = c.hits
+			total += len(c.hits)
+		}
+		if total == 0 {
+			t.Fatalf("%v: no colliders collided", phase.name)
+		}
+
+		if want == nil {
+			want = got
+			continue
+		}
+
+		for name, hits := range want {
+			if fmt.Sprint(got[name]) != fmt.Sprint(hits) {
+				t.Errorf("%v: %v collided with %v, want %v", phase.name, name, got[name], hits)
+			}
+		}
+	}
+}
+
+func TestSpatialHashClearDropsUnusedCells(t *testing.T) {
+	hash := NewSpatialHash(64)
+	bounds := NewRect(0, 0, 32, 32)
+	for i := 0; i < 10000; i++ {
+		hash.Clear()
+		bounds.X += 200
+		hash.Insert(i, bounds)
+	}
+
+	if cells := len(hash.cells); cells > 8 {
+		t.Errorf("spatial hash kept %v cells for a single collider", cells)
+	}
+
+	found := []interface{}{}
+	hash.Query(bounds, func(item interface{}) {
+		found = append(found, item)
+	})
+	if len(found) != 1 || found[0] != 9999 {
+		t.Errorf("query found %v, want [9999]", found)
+	}
+}
+
+func BenchmarkCollisions(b *testing.B) {
+	for _, phase := range broadPhases {
+		for _, n := range []int{100, 500, 2000} {
+			b.Run(fmt.Sprintf("%v/%v", phase.name, n), func(b *testing.B) {
+				engine, scene, _ := newCollisionScene(n, phase.new)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					scene.performCollisions(engine)
+				}
+			})
+		}
+	}
+}
+
+func TestSpatialHashHandlesHugeBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds Rect
+	}{
+		{"huge", NewRect(-1e12, -1e12, 2e12, 2e12)},
+		{"far away", NewRect(1e300, 1e300, 10, 10)},
+		{"infinite", NewRect(0, 0, math.Inf(1), 10)},
+		{"not a number", NewRect(math.NaN(), 0, 10, 10)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hash := NewSpatialHash(64)
+			hash.Insert("small", NewRect(0, 0, 10, 10))
+			hash.Insert("huge", test.bounds)
+
+			if cells := len(hash.cells); cells > 1 {
+				t.Errorf("spatial hash filled %v cells", cells)
+			}
+
+			for _, query := range []Rect{NewRect(0, 0, 10, 10), test.bounds} {
+				found := map[interface{}]int{}
+				hash.Query(query, func(item interface{}) {
+					found[item] += 1
+				})
+				if found["huge"] != 1 {
+					t.Errorf("query of %v found the huge item %v times, want 1", query, found["huge"])
+				}
+				if query == test.bounds && found["small"] != 1 {
+					t.Errorf("query of %v found the small item %v times, want 1", query, found["small"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package go2d
+
+import (
+	"math"
+)
+
+// Camera is a view into the world of a scene. Entities in world space layers
+// are drawn relative to the camera, while entities in screen space layers are
+// drawn directly onto the screen.
+type Camera struct {
+	// Position is the point in the world that is shown at the center of the
+	// screen.
+	Position Vector
+	// Zoom is the scale at which the world is drawn. A zoom of 2 draws
+	// everything twice as large.
+	Zoom float64
+	// Rotation is the rotation of the camera in radians.
+	Rotation float64
+	// Bounds is the area of the world that the camera is allowed to show. If
+	// it is zero, the camera can move freely.
+	Bounds Rect
+	// Deadzone is the size, in screen pixels, of the area around the center of
+	// the screen in which a followed target can move without moving the
+	// camera.
+	Deadzone Dimensions
+	// FollowSpeed is the fraction of the distance to the followed target that
+	// the camera moves each tick. A speed of 1 keeps the target locked in
+	// place, smaller values make the camera trail behind it smoothly.
+	FollowSpeed float64
+
+	screen   Dimensions
+	target   IEntity
+	previous Vector
+}
+
+// NewCamera creates a new camera for a screen with the given dimensions. The
+// camera starts out centered on the screen so that world space and screen
+// space line up.
+func NewCamera(screen Dimensions) *Camera {
+	center := Vector{
+		X: screen.Width / 2,
+		Y: screen.Height / 2,
+	}
+
+	return &Camera{
+		Position:    center,
+		Zoom:        1,
+		FollowSpeed: 1,
+		screen:      screen,
+		previous:    center,
+	}
+}
+
+// Follow makes the camera follow the given entity.
+func (this *Camera) Follow(target IEntity) {
+	this.target = target
+}
+
+// StopFollowing makes the camera stop following its target.
+func (this *Camera) StopFollowing() {
+	this.target = nil
+}
+
+// GetTarget returns the entity the camera is following, or nil.
+func (this *Camera) GetTarget() IEntity {
+	return this.target
+}
+
+// MoveTo moves the camera to the given position instantly.
+func (this *Camera) MoveTo(pos Vector) {
+	this.Position = pos
+	this.clamp()
+	this.previous = this.Position
+}
+
+// ScreenToWorld converts a position on the screen to a position in the world.
+func (this *Camera) ScreenToWorld(pos Vector) Vector {
+	return this.screenToWorld(pos, this.Position)
+}
+
+// WorldToScreen converts a position in the world to a position on the screen.
+func (this *Camera) WorldToScreen(pos Vector) Vector {
+	zoom := this.zoom()
+	sin, cos := math.Sincos(-this.Rotation)
+	dx := (pos.X - this.Position.X) * zoom
+	dy := (pos.Y - this.Position.Y) * zoom
+
+	return Vector{
+		X: this.screen.Width/2 + dx*cos - dy*sin,
+		Y: this.screen.Height/2 + dx*sin + dy*cos,
+	}
+}
+
+// VisibleArea returns the area of the world that is visible on the screen,
+// ignoring rotation.
+func (this *Camera) VisibleArea() Rect {
+	zoom := this.zoom()
+	w, h := this.screen.Width/zoom, this.screen.Height/zoom
+
+	return NewRect(this.Position.X-w/2, this.Position.Y-h/2, w, h)
+}
+
+func (this *Camera) screenToWorld(pos Vector, position Vector) Vector {
+	zoom := this.zoom()
+	sin, cos := math.Sincos(this.Rotation)
+	dx := (pos.X - this.screen.Width/2) / zoom
+	dy := (pos.Y - this.screen.Height/2) / zoom
+
+	return Vector{
+		X: position.X + dx*cos - dy*sin,
+		Y: position.Y + dx*sin + dy*cos,
+	}
+}
+
+func (this *Camera) zoom() float64 {
+	if this.Zoom <= 0 {
+		return 1
+	}
+
+	return this.Zoom
+}
+
+// update moves the camera towards its target and keeps it within its bounds.
+func (this *Camera) update(screen Dimensions) {
+	this.screen = screen
+	this.previous = this.Position
+
+	if this.target != nil {
+		target := this.target.GetEntity().Bounds.Center()
+		zoom := this.zoom()
+		halfDeadzone := Vector{
+			X: this.Deadzone.Width / zoom / 2,
+			Y: this.Deadzone.Height / zoom / 2,
+		}
+
+		desired := this.Position
+		if target.X < desired.X-halfDeadzone.X {
+			desired.X = target.X + halfDeadzone.X
+		} else if target.X > desired.X+halfDeadzone.X {
+			desired.X = target.X - halfDeadzone.X
+		}
+		if target.Y < desired.Y-halfDeadzone.Y {
+			desired.Y = target.Y + halfDeadzone.Y
+		} else if target.Y > desired.Y+halfDeadzone.Y {
+			desired.Y = target.Y - halfDeadzone.Y
+		}
+
+		speed := math.Max(0, math.Min(1, this.FollowSpeed))
+		this.Position = this.Position.Lerp(desired, speed)
+	}
+
+	this.clamp()
+}
+
+// clamp keeps the visible area of the camera within its bounds. If the bounds
+// are smaller than the visible area, the camera is centered on them.
+func (this *Camera) clamp() {
+	if this.Bounds.Width <= 0 || this.Bounds.Height <= 0 {
+		return
+	}
+
+	visible := this.VisibleArea()
+
+	if visible.Width >= this.Bounds.Width {
+		this.Position.X = this.Bounds.Center().X
+	} else {
+		this.Position.X = math.Max(this.Position.X, this.Bounds.X+visible.Width/2)
+		this.Position.X = math.Min(this.Position.X, this.Bounds.X+this.Bounds.Width-visible.Width/2)
+	}
+
+	if visible.Height >= this.Bounds.Height {
+		this.Position.Y = this.Bounds.Center().Y
+	} else {
+		this.Position.Y = math.Max(this.Position.Y, this.Bounds.Y+visible.Height/2)
+		this.Position.Y = math.Min(this.Position.Y, this.Bounds.Y+this.Bounds.Height-visible.Height/2)
+	}
+}
+
+// apply transforms the canvas of the engine so that drawing in world space
+// ends up in the right place on the screen. The position of the camera is
+// interpolated between ticks like the position of an entity.
+func (this *Camera) apply(e *Engine) {
+	position := this.previous.Lerp(this.Position, e.GetInterpolationAlpha())
+
+	e.transformCanvas(NewTranslateTransform(position.Inverted()).
+		Then(NewScaleTransform(Vector{X: this.zoom(), Y: this.zoom()})).
+		Then(NewRotateTransform(-this.Rotation)).
+		Then(NewTranslateTransform(Vector{X: this.screen.Width / 2, Y: this.screen.Height / 2})))
+}
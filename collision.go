@@ -0,0 +1,401 @@
+package go2d
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultCollisionCellSize is the cell size of the spatial hash that new
+// scenes use as their broad phase.
+const DefaultCollisionCellSize = 128
+
+// CollisionLayer is a set of collision layers stored as bit flags. Collision
+// layers are unrelated to the layers that entities are rendered in.
+type CollisionLayer uint32
+
+const (
+	// CollisionLayerNone is the empty set of collision layers.
+	CollisionLayerNone CollisionLayer = 0
+	// CollisionLayerDefault is the collision layer of colliders that do not
+	// implement IEntityCollisionFilter.
+	CollisionLayerDefault CollisionLayer = 1
+	// CollisionLayerAll is the set of every collision layer.
+	CollisionLayerAll CollisionLayer = ^CollisionLayer(0)
+)
+
+// NewCollisionLayer returns the collision layer with the given index, which
+// must be between 0 and 31.
+func NewCollisionLayer(index int) CollisionLayer {
+	return CollisionLayer(1) << uint(index)
+}
+
+// IEntityCollisionFilter is an interface that can be implemented by entities
+// that implement IEntityCollider or IEntityShapeCollider to limit which
+// colliders they interact with.
+// Two colliders only interact when each of their masks contains the layer of
+// the other. Colliders that do not implement it are in CollisionLayerDefault
+// and interact with every layer.
+type IEntityCollisionFilter interface {
+	// GetCollisionLayer returns the collision layers the collider belongs to.
+	GetCollisionLayer() CollisionLayer
+	// GetCollisionMask returns the collision layers the collider interacts
+	// with.
+	GetCollisionMask() CollisionLayer
+}
+
+// CollisionMatrix controls which collision layers are able to interact with
+// each other for an entire scene, in addition to the masks of each collider.
+type CollisionMatrix struct {
+	rows [32]CollisionLayer
+}
+
+// NewCollisionMatrix creates a new collision matrix in which every collision
+// layer interacts with every other collision layer.
+func NewCollisionMatrix() *CollisionMatrix {
+	matrix := &CollisionMatrix{}
+	for i := range matrix.rows {
+		matrix.rows[i] = CollisionLayerAll
+	}
+
+	return matrix
+}
+
+// SetCollides sets whether the colliders in collision layers a interact with
+// the colliders in collision layers b. The matrix is always symmetric.
+func (this *CollisionMatrix) SetCollides(a CollisionLayer, b CollisionLayer, collides bool) {
+	for i := range this.rows {
+		bit := NewCollisionLayer(i)
+		if a&bit != 0 {
+			this.rows[i] = setCollisionLayers(this.rows[i], b, collides)
+		}
+		if b&bit != 0 {
+			this.rows[i] = setCollisionLayers(this.rows[i], a, collides)
+		}
+	}
+}
+
+// Collides returns true if any of the collision layers in a interacts with any
+// of the collision layers in b.
+func (this *CollisionMatrix) Collides(a CollisionLayer, b CollisionLayer) bool {
+	for i := range this.rows {
+		if a&NewCollisionLayer(i) != 0 && this.rows[i]&b != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setCollisionLayers(layers CollisionLayer, other CollisionLayer, set bool) CollisionLayer {
+	if set {
+		return layers | other
+	}
+
+	return layers &^ other
+}
+
+// collider is a collider that was found while preparing the collision pass.
+// Its shape, bounds and movement are in the space of the scene, even when
+// its entity is inside of an entity group.
+type collider struct {
+	entity   interface{}
+	bounds   Rect
+	swept    Rect
+	shape    IShape
+	movement Vector
+	category CollisionLayer
+	mask     CollisionLayer
+	group    *EntityGroup
+	layer    int
+	key      interface{}
+	added    uint64
+	detector IEntityCollisionDetection
+	listens  bool
+	body     BodyType
+	index    int
+}
+
+// refresh updates the shape of the collider after its entity has moved.
+func (this *collider) refresh() {
+	if shape, isCollider := entityShape(this.entity); isCollider {
+		shape = transformShape(shape, this.group.sceneTransform())
+		this.shape = shape
+		this.bounds = shape.GetBounds()
+		this.swept = this.bounds
+		if !this.movement.IsZero() {
+			this.swept = unionRects(this.bounds, translateShape(shape, this.movement.Inverted()).GetBounds())
+		}
+	}
+}
+
+// contact is a pair of colliders that were colliding during a tick, as seen
+// by the first of them.
+type contact struct {
+	entity interface{}
+	other  interface{}
+}
+
+// interactsWith returns true if the collider should be tested against the
+// other collider according to their layers, masks and the collision matrix.
+func (this *collider) interactsWith(other *collider, matrix *CollisionMatrix) bool {
+	if this.mask&other.category == 0 || other.mask&this.category == 0 {
+		return false
+	}
+
+	return matrix == nil || matrix.Collides(this.category, other.category)
+}
+
+// overlaps returns true if the collider overlaps the other collider, or if
+// either of them is a bullet that passed through the other during the tick.
+func (this *collider) overlaps(other *collider) bool {
+	if !this.swept.IntersectsWith(other.swept) {
+		return false
+	}
+
+	if this.intersects(other) {
+		return true
+	}
+
+	_, hits := this.sweep(other)
+	return hits
+}
+
+// sweep returns when the collider first touched the other collider during
+// the tick, based on how far each of them moved.
+func (this *collider) sweep(other *collider) (SweepHit, bool) {
+	movement := this.movement.Sub(other.movement)
+	if movement.IsZero() {
+		return SweepHit{}, false
+	}
+
+	return SweepShape(
+		translateShape(this.shape, this.movement.Inverted()),
+		movement,
+		translateShape(other.shape, other.movement.Inverted()),
+	)
+}
+
+// intersects returns true if the collider intersects the other collider
+// where they are now.
+func (this *collider) intersects(other *collider) bool {
+	if !this.bounds.IntersectsWith(other.bounds) {
+		return false
+	}
+
+	_, isRect := this.shape.(Rect)
+	_, isOtherRect := other.shape.(Rect)
+	if isRect && isOtherRect {
+		return true
+	}
+
+	return ShapesIntersect(this.shape, other.shape)
+}
+
+// entityShape returns the shape of an entity that implements
+// IEntityShapeCollider or IEntityCollider.
+func entityShape(e interface{}) (IShape, bool) {
+	if shapeCollider, isShapeCollider := e.(IEntityShapeCollider); isShapeCollider {
+		return shapeCollider.GetShape(), true
+	}
+
+	if rectCollider, isRectCollider := e.(IEntityCollider); isRectCollider {
+		return rectCollider.GetCollider(), true
+	}
+
+	return nil, false
+}
+
+// bulletMovement returns how far an entity that implements IEntityBullet
+// has moved since the start of the tick.
+func bulletMovement(e interface{}) Vector {
+	bullet, isBullet := e.(IEntityBullet)
+	entity, isEntity := e.(IEntity)
+	if !isBullet || !isEntity || !bullet.IsBullet() {
+		return Vector{}
+	}
+
+	return entity.GetEntity().Bounds.Vector.Sub(entity.GetEntity().previous)
+}
+
+// collisionFilter returns the collision layers and mask of a collider.
+func collisionFilter(e interface{}) (CollisionLayer, CollisionLayer) {
+	if filter, isFiltered := e.(IEntityCollisionFilter); isFiltered {
+		return filter.GetCollisionLayer(), filter.GetCollisionMask()
+	}
+
+	return CollisionLayerDefault, CollisionLayerAll
+}
+
+// collectColliders returns every entity in the scene, including the entities
+// inside of entity groups, that implements IEntityShapeCollider or
+// IEntityCollider along with its shape for the current tick.
+func (this *Scene) collectColliders() []*collider {
+	colliders := []*collider{}
+	this.walkEntitiesIn(func(group *EntityGroup, layer int, key interface{}, value interface{}) {
+		if _, isCollider := entityShape(value); !isCollider {
+			return
+		}
+
+		category, mask := collisionFilter(value)
+		detector, _ := value.(IEntityCollisionDetection)
+		_, isEnterHandler := value.(IEntityCollisionEnterHandler)
+		_, isStayHandler := value.(IEntityCollisionStayHandler)
+		_, isExitHandler := value.(IEntityCollisionExitHandler)
+		body := BodyTypeNone
+		if _, isRigidBody := value.(IEntityRigidBody); isRigidBody {
+			// Rigid bodies are moved by the physics world, but other
+			// solid bodies are still pushed out of them.
+			body = BodyTypeKinematic
+		} else if b, isBody := value.(IEntityBody); isBody {
+			body = b.GetBodyType()
+		}
+		colliders = append(colliders, &collider{
+			entity:   value,
+			movement: group.sceneTransform().ApplyVector(bulletMovement(value)),
+			category: category,
+			mask:     mask,
+			group:    group,
+			layer:    layer,
+			key:      key,
+			added:    group.addedAt(layer, key),
+			detector: detector,
+			listens:  detector != nil || isEnterHandler || isStayHandler || isExitHandler,
+			body:     body,
+		})
+		colliders[len(colliders)-1].refresh()
+	})
+
+	// The entities of each layer are stored in a sync.Map, which is iterated
+	// in a random order, so the colliders are sorted by when their entities
+	// were added to keep the collision pass deterministic.
+	sort.Slice(colliders, func(i, j int) bool {
+		return colliders[i].added < colliders[j].added
+	})
+	for i, c := range colliders {
+		c.index = i
+	}
+
+	return colliders
+}
+
+// isInScene returns true if neither the collider nor any of the entity groups
+// it is inside of have been removed from the scene since the collision pass
+// started.
+func (this *Scene) isInScene(c *collider) bool {
+	entities, _ := c.group.entities.Load(c.layer)
+	if entities == nil {
+		return false
+	}
+
+	if _, exists := entities.(*sync.Map).Load(c.key); !exists {
+		return false
+	}
+
+	for group := c.group; group != this.EntityGroup; group = group.parent {
+		if group == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// performCollisions notifies every IEntityCollisionDetection entity of the
+// colliders it intersects with and interacts with. Contacts are remembered
+// between ticks to notify the collision enter, stay and exit handlers. When
+// the scene has a broad phase, only the colliders near each other are tested,
+// otherwise every pair is tested. Finally, the contacts of the physics world
+// are solved and solid bodies that overlap are pushed apart.
+func (this *Scene) performCollisions(engine *Engine) {
+	changes := entitiesChanged.Load()
+	colliders := this.collectColliders()
+	this.colliding = true
+	defer func() {
+		this.colliding = false
+	}()
+	contacts := map[contact]*collider{}
+
+	var nearby func(c *collider, cb func(other *collider))
+	if this.BroadPhase != nil {
+		this.BroadPhase.Clear()
+		for _, c := range colliders {
+			this.BroadPhase.Insert(c, c.swept)
+		}
+
+		nearby = func(c *collider, cb func(other *collider)) {
+			this.BroadPhase.Query(c.swept, func(item interface{}) {
+				cb(item.(*collider))
+			})
+		}
+	} else {
+		nearby = func(c *collider, cb func(other *collider)) {
+			for _, other := range colliders {
+				cb(other)
+			}
+		}
+	}
+
+	for _, c := range colliders {
+		// Entities are commonly removed from the scene when they collide,
+		// and removed entities should no longer collide with anything.
+		if !c.listens || !this.isInScene(c) {
+			continue
+		}
+
+		nearby(c, func(other *collider) {
+			if other == c || !c.interactsWith(other, this.CollisionMatrix) {
+				return
+			}
+
+			if !c.overlaps(other) || !this.isInScene(other) {
+				return
+			}
+
+			// A collider that removes itself is still told about the rest
+			// of the colliders it collided with during the tick, but its
+			// contacts are no longer tracked.
+			if c.detector != nil {
+				c.detector.CollidedWith(other.entity)
+			}
+
+			if !this.isInScene(c) {
+				return
+			}
+
+			key := contact{entity: c.entity, other: other.entity}
+			contacts[key] = c
+			if _, touching := this.contacts[key]; touching {
+				if handler, isHandler := c.entity.(IEntityCollisionStayHandler); isHandler {
+					handler.OnCollisionStay(other.entity)
+				}
+			} else {
+				if handler, isHandler := c.entity.(IEntityCollisionEnterHandler); isHandler {
+					handler.OnCollisionEnter(other.entity)
+				}
+			}
+		})
+	}
+
+	for key, c := range this.contacts {
+		if _, touching := contacts[key]; touching {
+			continue
+		}
+
+		handler, isHandler := c.entity.(IEntityCollisionExitHandler)
+		if isHandler && this.isInScene(c) {
+			handler.OnCollisionExit(key.other)
+		}
+	}
+
+	this.contacts = contacts
+
+	if this.Physics != nil {
+		this.Physics.solve(this, colliders, nearby)
+	}
+
+	this.resolveCollisions(colliders, nearby)
+
+	// Queries keep using the broad phase until the next collision pass,
+	// after updating the bounds of the colliders that moved.
+	this.queryable, this.queryableAt, this.queryablePhase = colliders, changes, this.BroadPhase
+}
@@ -0,0 +1,142 @@
+package go2d
+
+import (
+	"fmt"
+	"testing"
+)
+
+// selfRemovingCollider removes itself from its scene when it collides with
+// anything, like a bullet.
+type selfRemovingCollider struct {
+	testCollider
+	scene  *Scene
+	enters int
+}
+
+func (this *selfRemovingCollider) CollidedWith(other interface{}) {
+	this.testCollider.CollidedWith(other)
+	this.scene.RemoveEntity(0, this.name)
+}
+
+func (this *selfRemovingCollider) OnCollisionEnter(other interface{}) {
+	this.enters += 1
+}
+
+// solidCollider is a collider that does not listen for collisions.
+type solidCollider struct {
+	Entity
+	name string
+}
+
+func (this *solidCollider) GetCollider() Rect {
+	return this.Bounds
+}
+
+func (this *solidCollider) colliderName() string {
+	return this.name
+}
+
+func TestSelfRemovingColliderSeesEveryOverlap(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	defer engine.Stop()
+	scene := NewScene(engine, "collisions")
+
+	bullet := &selfRemovingCollider{
+		testCollider: testCollider{name: "bullet"},
+		scene:        &scene,
+	}
+	bullet.Bounds = NewRect(10, 10, 10, 10)
+	scene.AddNamedEntity(bullet.name, 0, bullet)
+
+	for _, name := range []string{"a", "b"} {
+		enemy := &solidCollider{name: name}
+		enemy.Bounds = NewRect(15, 15, 10, 10)
+		scene.AddNamedEntity(name, 0, enemy)
+	}
+
+	engine.SetScene(&scene)
+	engine.Step()
+
+	if len(bullet.hits) != 2 {
+		t.Errorf("bullet collided with %v, want both enemies", bullet.hits)
+	}
+	if bullet.enters != 0 {
+		t.Errorf("removed bullet got %v collision enter events", bullet.enters)
+	}
+	if scene.GetEntity(0, "bullet") != nil {
+		t.Errorf("bullet was not removed")
+	}
+}
+
+func TestNestedCollidersCollideInSceneSpace(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	defer engine.Stop()
+	scene := NewScene(engine, "collisions")
+
+	group := NewEntityGroup()
+	group.Bounds = NewRect(100, 100, 50, 50)
+	scene.AddNamedEntity("group", 0, group)
+
+	nested := &testCollider{name: "nested"}
+	nested.Bounds = NewRect(10, 10, 10, 10)
+	group.AddNamedEntity(nested.name, 0, nested)
+
+	near := &solidCollider{name: "near"}
+	near.Bounds = NewRect(115, 115, 10, 10)
+	scene.AddNamedEntity(near.name, 0, near)
+
+	far := &solidCollider{name: "far"}
+	far.Bounds = NewRect(15, 15, 10, 10)
+	scene.AddNamedEntity(far.name, 0, far)
+
+	scene.performCollisions(engine)
+	if len(nested.hits) != 1 || nested.hits[0] != "near" {
+		t.Fatalf("nested collider collided with %v, want [near]", nested.hits)
+	}
+
+	scene.RemoveEntity(0, "group")
+	nested.hits = nil
+	scene.performCollisions(engine)
+	if len(nested.hits) != 0 {
+		t.Errorf("collider in a removed group collided with %v", nested.hits)
+	}
+}
+
+// plainCollider is a collider that is not an entity.
+type plainCollider struct {
+	name   string
+	bounds Rect
+}
+
+func (this *plainCollider) GetCollider() Rect {
+	return this.bounds
+}
+
+func (this *plainCollider) colliderName() string {
+	return this.name
+}
+
+func TestCollidersAreTestedInTheOrderTheyWereAdded(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	scene := NewScene(engine, "collisions")
+	scene.BroadPhase = nil
+
+	listener := &testCollider{name: "listener"}
+	listener.Bounds = NewRect(0, 0, 100, 100)
+	scene.AddNamedEntity(listener.name, 0, listener)
+
+	want := []string{}
+	for i := 0; i < 20; i++ {
+		c := &plainCollider{name: fmt.Sprintf("c%v", i), bounds: NewRect(float64(i), 0, 10, 10)}
+		scene.AddNamedEntity(c.name, i%3, c)
+		want = append(want, c.name)
+	}
+
+	for run := 0; run < 5; run++ {
+		listener.hits = nil
+		scene.performCollisions(engine)
+		if fmt.Sprint(listener.hits) != fmt.Sprint(want) {
+			t.Fatalf("collided with %v, want %v", listener.hits, want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package go2d
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// styleColor returns the color of a color string in one of the formats that
+// are accepted by the canvas: "#rgb", "#rgba", "#rrggbb" or "#rrggbbaa".
+// Color strings that can not be parsed are returned as white, so that tinting
+// them leaves them unchanged.
+func styleColor(style string) color.RGBA {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	if !strings.HasPrefix(style, "#") {
+		return white
+	}
+
+	digits := style[1:]
+	if len(digits) == 3 || len(digits) == 4 {
+		expanded := ""
+		for _, digit := range digits {
+			expanded += string(digit) + string(digit)
+		}
+		digits = expanded
+	}
+	if len(digits) == 6 {
+		digits += "ff"
+	}
+
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || len(digits) != 8 {
+		return white
+	}
+
+	return color.RGBA{
+		R: uint8(value >> 24),
+		G: uint8(value >> 16),
+		B: uint8(value >> 8),
+		A: uint8(value),
+	}
+}
+
+// multiplyColors returns the product of two colors, treating a nil color as
+// white.
+func multiplyColors(a color.Color, b color.Color) color.Color {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	ar, ag, ab, aa := unpremultipliedColor(a)
+	br, bg, bb, ba := unpremultipliedColor(b)
+	return color.NRGBA{
+		R: uint8(ar * br / 255),
+		G: uint8(ag * bg / 255),
+		B: uint8(ab * bb / 255),
+		A: uint8(aa * ba / 255),
+	}
+}
+
+// tintStyle returns the color string multiplied with the given tint, or the
+// color string itself if there is no tint.
+func tintStyle(style string, tint color.Color) interface{} {
+	if tint == nil {
+		return style
+	}
+
+	c := styleColor(style)
+	return multiplyColors(color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}, tint)
+}
+
+// tintImage returns a copy of the image with every pixel multiplied with the
+// given tint.
+func tintImage(img image.Image, tint color.Color) *image.RGBA {
+	tr, tg, tb, ta := unpremultipliedColor(tint)
+	bounds := img.Bounds()
+	tinted := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			// The canvas treats the pixels of RGBA images as not being
+			// multiplied by alpha, so the tinted image is stored that way too.
+			var r, g, b, a uint32
+			if rgba, isRGBA := img.(*image.RGBA); isRGBA {
+				c := rgba.RGBAAt(bounds.Min.X+x, bounds.Min.Y+y)
+				r, g, b, a = uint32(c.R), uint32(c.G), uint32(c.B), uint32(c.A)
+			} else {
+				r, g, b, a = unpremultipliedColor(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			}
+
+			tinted.SetRGBA(x, y, color.RGBA{
+				R: uint8(r * tr / 255),
+				G: uint8(g * tg / 255),
+				B: uint8(b * tb / 255),
+				A: uint8(a * ta / 255),
+			})
+		}
+	}
+
+	return tinted
+}
+
+// unpremultipliedColor returns the red, green, blue and alpha channels of the
+// color between 0 and 255, without the color channels multiplied by alpha.
+func unpremultipliedColor(c color.Color) (uint32, uint32, uint32, uint32) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return uint32(n.R), uint32(n.G), uint32(n.B), uint32(n.A)
+}
+
+// tintedImages caches tinted copies of the images of an entity for the tint
+// they were last drawn with.
+type tintedImages struct {
+	tint   color.NRGBA
+	images map[image.Image]*image.RGBA
+}
+
+// get returns a copy of the image multiplied with the given tint.
+func (this *tintedImages) get(img image.Image, tint color.Color) *image.RGBA {
+	key := color.NRGBAModel.Convert(tint).(color.NRGBA)
+	if this.images == nil || this.tint != key {
+		this.tint = key
+		this.images = map[image.Image]*image.RGBA{}
+	}
+
+	tinted, cached := this.images[img]
+	if !cached {
+		tinted = tintImage(img, tint)
+		this.images[img] = tinted
+	}
+
+	return tinted
+}
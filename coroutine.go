@@ -0,0 +1,271 @@
+package go2d
+
+import (
+	"runtime"
+	"time"
+)
+
+// Coroutine is a function that runs alongside the updates of a scene and can
+// wait for time to pass or for something to happen before continuing, which
+// makes it possible to write long sequences, such as cutscenes or attack
+// patterns, as straight line code.
+//
+// Each coroutine runs on its own goroutine, but only while the scene resumes
+// it from its update, so coroutines never run at the same time as each other
+// or as the rest of the game. They are resumed in the order that they were
+// started, once per tick, before the entities of the scene are updated.
+type Coroutine struct {
+	fn      func(co *Coroutine)
+	resume  chan struct{}
+	yield   chan interface{}
+	until   func(tick time.Duration) bool
+	started bool
+	running bool
+	stopped bool
+	done    bool
+}
+
+// coroutinePanic wraps a value that a coroutine panicked with, so that the
+// panic can be raised again by whoever resumed the coroutine.
+type coroutinePanic struct {
+	value interface{}
+}
+
+// NewCoroutine creates a new coroutine that runs the given function. The
+// coroutine does not run until it is added to a scene with AddCoroutine.
+func NewCoroutine(fn func(co *Coroutine)) *Coroutine {
+	return &Coroutine{
+		fn:     fn,
+		resume: make(chan struct{}),
+		yield:  make(chan interface{}),
+	}
+}
+
+// Wait suspends the coroutine until the given amount of tick time has
+// passed.
+func (this *Coroutine) Wait(d time.Duration) {
+	remaining := d
+	this.suspend(func(tick time.Duration) bool {
+		remaining -= tick
+		return remaining <= 0
+	})
+}
+
+// WaitTicks suspends the coroutine for the given number of ticks.
+func (this *Coroutine) WaitTicks(n int) {
+	if n <= 0 {
+		return
+	}
+
+	ticks := 0
+	this.suspend(func(tick time.Duration) bool {
+		ticks += 1
+		return ticks >= n
+	})
+}
+
+// WaitUntil suspends the coroutine until the given function returns true. The
+// function is called once per tick, and the coroutine is not suspended at all
+// if it already returns true.
+func (this *Coroutine) WaitUntil(condition func() bool) {
+	if condition() {
+		return
+	}
+
+	this.suspend(func(tick time.Duration) bool {
+		return condition()
+	})
+}
+
+// Parallel runs each of the given functions as a coroutine of its own and
+// suspends the coroutine until all of them have finished. If one of them
+// panics, the others are stopped and the panic is raised again in the
+// coroutine.
+func (this *Coroutine) Parallel(fns ...func(co *Coroutine)) {
+	children := newChildCoroutines(fns)
+	defer stopCoroutines(children)
+
+	var failure *coroutinePanic
+	finished := func(tick time.Duration) bool {
+		all := true
+		for _, child := range children {
+			if failure = stepChild(child, tick); failure != nil {
+				return true
+			}
+			all = all && child.done
+		}
+		return all
+	}
+
+	if !finished(0) {
+		this.suspend(finished)
+	}
+	raiseChildPanic(children, failure)
+}
+
+// Race runs each of the given functions as a coroutine of its own and
+// suspends the coroutine until the first of them has finished. The others
+// are stopped, and the index of the function that finished first is
+// returned. If one of them panics, the others are stopped and the panic is
+// raised again in the coroutine.
+func (this *Coroutine) Race(fns ...func(co *Coroutine)) int {
+	children := newChildCoroutines(fns)
+	defer stopCoroutines(children)
+
+	winner := -1
+	var failure *coroutinePanic
+	finished := func(tick time.Duration) bool {
+		for i, child := range children {
+			if failure = stepChild(child, tick); failure != nil {
+				return true
+			}
+			if child.done {
+				winner = i
+				return true
+			}
+		}
+		return false
+	}
+
+	if !finished(0) {
+		this.suspend(finished)
+	}
+	raiseChildPanic(children, failure)
+
+	return winner
+}
+
+// Stop ends the coroutine, running any functions it has deferred. A coroutine
+// that is running, because it stopped itself or was stopped by a coroutine
+// that it is running, ends the next time it waits instead.
+func (this *Coroutine) Stop() {
+	if this.done {
+		return
+	}
+
+	this.stopped = true
+	if this.running {
+		return
+	}
+
+	if this.started {
+		this.resume <- struct{}{}
+		<-this.yield
+	}
+	this.done = true
+}
+
+// IsDone returns true if the coroutine has finished or was stopped.
+func (this *Coroutine) IsDone() bool {
+	return this.done
+}
+
+// suspend hands control back to whoever resumed the coroutine until the given
+// function returns true for a tick.
+func (this *Coroutine) suspend(until func(tick time.Duration) bool) {
+	this.until = until
+	this.running = false
+	this.yield <- nil
+	<-this.resume
+	this.running = true
+
+	if this.stopped {
+		runtime.Goexit()
+	}
+}
+
+// step resumes the coroutine if what it is waiting for has happened during a
+// tick of the given duration, and blocks until it suspends itself again or
+// finishes.
+func (this *Coroutine) step(tick time.Duration) {
+	if this.done {
+		return
+	}
+
+	if this.until != nil && !this.until(tick) {
+		return
+	}
+	this.until = nil
+
+	// The coroutine may have been stopped while checking what it is
+	// waiting for.
+	if this.done {
+		return
+	}
+
+	if !this.started {
+		this.started = true
+		go this.run()
+	}
+
+	this.resume <- struct{}{}
+	if result := <-this.yield; result != nil {
+		panic(result.(coroutinePanic).value)
+	}
+}
+
+// run is the body of the goroutine of the coroutine.
+func (this *Coroutine) run() {
+	var result interface{}
+	defer func() {
+		this.done = true
+		this.running = false
+		this.yield <- result
+	}()
+	defer func() {
+		// A coroutine that panics is done, and the panic is raised again
+		// on the goroutine that resumed it.
+		if p := recover(); p != nil {
+			result = coroutinePanic{value: p}
+		}
+	}()
+
+	<-this.resume
+	if this.stopped {
+		return
+	}
+
+	this.running = true
+	this.fn(this)
+}
+
+func newChildCoroutines(fns []func(co *Coroutine)) []*Coroutine {
+	children := make([]*Coroutine, len(fns))
+	for i, fn := range fns {
+		children[i] = NewCoroutine(fn)
+	}
+
+	return children
+}
+
+func stopCoroutines(coroutines []*Coroutine) {
+	for _, co := range coroutines {
+		co.Stop()
+	}
+}
+
+// stepChild resumes a child coroutine for a tick and returns what it panicked
+// with, if anything. The panic is recovered because it is raised while the
+// parent coroutine is checking whether it can continue, which happens on the
+// goroutine that resumes the parent rather than on the parent itself.
+func stepChild(child *Coroutine, tick time.Duration) (failure *coroutinePanic) {
+	defer func() {
+		if p := recover(); p != nil {
+			failure = &coroutinePanic{value: p}
+		}
+	}()
+
+	child.step(tick)
+	return nil
+}
+
+// raiseChildPanic stops the child coroutines and raises the panic of the one
+// that panicked, if any, on the goroutine of the parent coroutine.
+func raiseChildPanic(children []*Coroutine, failure *coroutinePanic) {
+	if failure == nil {
+		return
+	}
+
+	stopCoroutines(children)
+	panic(failure.value)
+}
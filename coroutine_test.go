@@ -0,0 +1,214 @@
+package go2d
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCoroutineWaitsInOrder(t *testing.T) {
+	engine, scene := newTestScene()
+
+	tick := 0
+	events := []string{}
+	record := func(event string) {
+		events = append(events, fmt.Sprintf("%v on tick %v", event, tick))
+	}
+
+	scene.StartCoroutine(func(co *Coroutine) {
+		record("started")
+		co.Wait(30 * engine.GetTickDuration())
+		record("waited")
+		co.WaitTicks(10)
+		record("waited ticks")
+		co.WaitUntil(func() bool {
+			return tick >= 50
+		})
+		record("waited until")
+		co.WaitTicks(0)
+		co.WaitUntil(func() bool {
+			return true
+		})
+		record("finished")
+	})
+
+	for tick = 1; tick <= 60; tick++ {
+		scene.performUpdate(engine)
+	}
+
+	want := []string{
+		"started on tick 1",
+		"waited on tick 31",
+		"waited ticks on tick 41",
+		"waited until on tick 50",
+		"finished on tick 50",
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+	if len(scene.coroutines) != 0 {
+		t.Errorf("the scene still has %v coroutines, want 0", len(scene.coroutines))
+	}
+}
+
+func TestCoroutineParallelWaitsForAll(t *testing.T) {
+	engine, scene := newTestScene()
+
+	tick := 0
+	finished := []int{}
+	co := scene.StartCoroutine(func(co *Coroutine) {
+		co.Parallel(
+			func(co *Coroutine) {
+				co.WaitTicks(5)
+				finished = append(finished, tick)
+			},
+			func(co *Coroutine) {
+				co.WaitTicks(10)
+				finished = append(finished, tick)
+			},
+			func(co *Coroutine) {
+				finished = append(finished, tick)
+			},
+		)
+		finished = append(finished, tick)
+	})
+
+	for tick = 1; tick <= 20; tick++ {
+		scene.performUpdate(engine)
+	}
+
+	if want := []int{1, 6, 11, 11}; fmt.Sprint(finished) != fmt.Sprint(want) {
+		t.Errorf("finished on ticks %v, want %v", finished, want)
+	}
+	if !co.IsDone() {
+		t.Errorf("the coroutine is not done")
+	}
+}
+
+func TestCoroutineRaceStopsTheLosers(t *testing.T) {
+	engine, scene := newTestScene()
+
+	tick := 0
+	winner, wonOn := -1, 0
+	stopped := []int{}
+	finished := []int{}
+	racer := func(i int, ticks int) func(co *Coroutine) {
+		return func(co *Coroutine) {
+			defer func() {
+				stopped = append(stopped, i)
+			}()
+			co.WaitTicks(ticks)
+			finished = append(finished, i)
+		}
+	}
+
+	scene.StartCoroutine(func(co *Coroutine) {
+		winner = co.Race(racer(0, 10), racer(1, 5), racer(2, 20))
+		wonOn = tick
+	})
+
+	for tick = 1; tick <= 30; tick++ {
+		scene.performUpdate(engine)
+	}
+
+	if winner != 1 || wonOn != 6 {
+		t.Errorf("racer %v won on tick %v, want racer 1 on tick 6", winner, wonOn)
+	}
+	if fmt.Sprint(finished) != "[1]" {
+		t.Errorf("racers %v finished, want only racer 1", finished)
+	}
+	if fmt.Sprint(stopped) != "[1 0 2]" {
+		t.Errorf("racers %v ended, want [1 0 2]", stopped)
+	}
+}
+
+func TestCoroutineStoppedWhileWaiting(t *testing.T) {
+	engine, scene := newTestScene()
+
+	reached := false
+	deferred := []string{}
+	co := scene.StartCoroutine(func(co *Coroutine) {
+		defer func() {
+			deferred = append(deferred, "parent")
+		}()
+		co.Parallel(func(co *Coroutine) {
+			defer func() {
+				deferred = append(deferred, "child")
+			}()
+			co.WaitTicks(10)
+			reached = true
+		})
+		reached = true
+	})
+
+	updateScene(engine, scene, 5)
+	co.Stop()
+	updateScene(engine, scene, 10)
+
+	if !co.IsDone() || reached {
+		t.Errorf("done = %v and continued = %v after stopping, want done without continuing", co.IsDone(), reached)
+	}
+	if fmt.Sprint(deferred) != "[child parent]" {
+		t.Errorf("deferred functions ran for %v, want [child parent]", deferred)
+	}
+	if len(scene.coroutines) != 0 {
+		t.Errorf("the scene still has %v coroutines, want 0", len(scene.coroutines))
+	}
+}
+
+func TestCoroutinePanicsAreRaisedInTheParent(t *testing.T) {
+	tests := []struct {
+		name string
+		wait func(co *Coroutine, fns ...func(co *Coroutine))
+	}{
+		{"parallel", (*Coroutine).Parallel},
+		{"race", func(co *Coroutine, fns ...func(co *Coroutine)) {
+			co.Race(fns...)
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine, scene := newTestScene()
+
+			deferred := []string{}
+			parentRecovered := interface{}(nil)
+			co := scene.StartCoroutine(func(co *Coroutine) {
+				defer func() {
+					parentRecovered = recover()
+					deferred = append(deferred, "parent")
+					panic(parentRecovered)
+				}()
+				test.wait(co,
+					func(co *Coroutine) {
+						co.WaitTicks(3)
+						panic("boom")
+					},
+					func(co *Coroutine) {
+						defer func() {
+							deferred = append(deferred, "sibling")
+						}()
+						co.WaitTicks(10)
+					},
+				)
+			})
+
+			var recovered interface{}
+			func() {
+				defer func() {
+					recovered = recover()
+				}()
+				updateScene(engine, scene, 10)
+			}()
+
+			if recovered != "boom" || parentRecovered != "boom" {
+				t.Errorf("the parent recovered %v and the scene recovered %v, want boom for both", parentRecovered, recovered)
+			}
+			if fmt.Sprint(deferred) != "[sibling parent]" {
+				t.Errorf("deferred functions ran for %v, want [sibling parent]", deferred)
+			}
+			if !co.IsDone() {
+				t.Errorf("the coroutine is not done after panicking")
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package go2d
+
+import (
+	"math"
+)
+
+// EasingFunc is a function that maps the linear progress of a tween, from 0
+// to 1, to the eased progress of the tween. The eased progress starts at 0 and
+// ends at 1, but may go past them in between.
+type EasingFunc func(t float64) float64
+
+const (
+	easingBackOvershoot = 1.70158
+	easingBounceSlope   = 7.5625
+	easingBounceWidth   = 2.75
+)
+
+// EaseLinear progresses at a constant rate.
+func EaseLinear(t float64) float64 {
+	return t
+}
+
+// EaseInQuad starts slowly and accelerates.
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseOutQuad starts quickly and decelerates.
+func EaseOutQuad(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// EaseInOutQuad accelerates until halfway and then decelerates.
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+
+	return 1 - math.Pow(-2*t+2, 2)/2
+}
+
+// EaseInCubic starts slowly and accelerates, more sharply than EaseInQuad.
+func EaseInCubic(t float64) float64 {
+	return t * t * t
+}
+
+// EaseOutCubic starts quickly and decelerates, more sharply than
+// EaseOutQuad.
+func EaseOutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+// EaseInOutCubic accelerates until halfway and then decelerates, more sharply
+// than EaseInOutQuad.
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+// EaseInElastic oscillates around the start with growing amplitude before
+// snapping to the end.
+func EaseInElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+
+	return -math.Pow(2, 10*t-10) * math.Sin((10*t-10.75)*(2*math.Pi/3))
+}
+
+// EaseOutElastic overshoots the end and oscillates around it with shrinking
+// amplitude, like a spring.
+func EaseOutElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+
+	return math.Pow(2, -10*t)*math.Sin((10*t-0.75)*(2*math.Pi/3)) + 1
+}
+
+// EaseInOutElastic oscillates around the start and then around the end.
+func EaseInOutElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+
+	if t < 0.5 {
+		return -(math.Pow(2, 20*t-10) * math.Sin((20*t-11.125)*(2*math.Pi/4.5))) / 2
+	}
+
+	return math.Pow(2, -20*t+10)*math.Sin((20*t-11.125)*(2*math.Pi/4.5))/2 + 1
+}
+
+// EaseInBounce bounces off of the start with growing height before reaching
+// the end.
+func EaseInBounce(t float64) float64 {
+	return 1 - EaseOutBounce(1-t)
+}
+
+// EaseOutBounce reaches the end and bounces off of it with shrinking height,
+// like a dropped ball.
+func EaseOutBounce(t float64) float64 {
+	switch {
+	case t < 1/easingBounceWidth:
+		return easingBounceSlope * t * t
+	case t < 2/easingBounceWidth:
+		t -= 1.5 / easingBounceWidth
+		return easingBounceSlope*t*t + 0.75
+	case t < 2.5/easingBounceWidth:
+		t -= 2.25 / easingBounceWidth
+		return easingBounceSlope*t*t + 0.9375
+	default:
+		t -= 2.625 / easingBounceWidth
+		return easingBounceSlope*t*t + 0.984375
+	}
+}
+
+// EaseInOutBounce bounces off of the start and then off of the end.
+func EaseInOutBounce(t float64) float64 {
+	if t < 0.5 {
+		return (1 - EaseOutBounce(1-2*t)) / 2
+	}
+
+	return (1 + EaseOutBounce(2*t-1)) / 2
+}
+
+// EaseInBack pulls back past the start before moving to the end.
+func EaseInBack(t float64) float64 {
+	return (easingBackOvershoot+1)*t*t*t - easingBackOvershoot*t*t
+}
+
+// EaseOutBack overshoots the end before settling on it.
+func EaseOutBack(t float64) float64 {
+	return 1 + (easingBackOvershoot+1)*math.Pow(t-1, 3) + easingBackOvershoot*math.Pow(t-1, 2)
+}
+
+// EaseInOutBack pulls back past the start and overshoots the end.
+func EaseInOutBack(t float64) float64 {
+	overshoot := easingBackOvershoot * 1.525
+	if t < 0.5 {
+		return math.Pow(2*t, 2) * ((overshoot+1)*2*t - overshoot) / 2
+	}
+
+	return (math.Pow(2*t-2, 2)*((overshoot+1)*(t*2-2)+overshoot) + 2) / 2
+}
@@ -1,13 +1,14 @@
 package go2d
 
 import (
-	"fmt"
+	"context"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/tfriedel6/canvas"
-	"github.com/tfriedel6/canvas/sdlcanvas"
-	"github.com/veandco/go-sdl2/sdl"
+	"github.com/tfriedel6/canvas/backend/softwarebackend"
 )
 
 type IFPSUpdateHandler interface {
@@ -18,76 +19,129 @@ type ITPSUpdateHandler interface {
 	OnTPSUpdated(*Engine, int)
 }
 
+type IRenderErrorHandler interface {
+	OnRenderError(*Engine, error)
+}
+
 // Engine is the main engine that handles the game loop and rendering.
 type Engine struct {
 	// Name is the name of the game window.
 	Name string
-	// Dimensions is the dimensions of the game window.
+	// Dimensions is the logical resolution of the game. The game is always
+	// drawn in this resolution and then scaled to fit the window using the
+	// ScaleMode.
 	Dimensions Dimensions
+	// ScaleMode is how the logical resolution is scaled to fit the window.
+	ScaleMode ScaleMode
+	// AspectRatio is the aspect ratio kept by ScaleModeLetterbox. If it is nil,
+	// the aspect ratio of Dimensions is used.
+	AspectRatio *AspectRatio
 	// FPSUpdateHandler is the handler for when the FPS is updated.
 	FPSUpdateHandler IFPSUpdateHandler
 	// TPSUpdateHandler is the handler for when the TPS is updated.
 	TPSUpdateHandler ITPSUpdateHandler
+	// RenderErrorHandler is the handler for errors that occur while rendering,
+	// such as an image that can not be loaded. If it is nil, the engine panics
+	// with the error instead.
+	RenderErrorHandler IRenderErrorHandler
 	// Canvas is the canvas that is used to render the game.
 	Canvas *canvas.Canvas
 	// HideCursor is a flag that determines if the cursor should be hidden.
 	HideCursor bool
-	// MaxTPS is the maximum TPS that the game should run at.
+	// MaxTPS is the maximum TPS that the game should run at. The game is
+	// updated in fixed steps of 1/MaxTPS seconds regardless of the FPS.
 	MaxTPS int
-
-	scene      *Scene
-	window     *sdlcanvas.Window
-	currentFps int
-	currentTps int
-	renderMux  sync.Mutex
+	// MaxCatchUpTicks is the maximum number of ticks that will be performed
+	// back to back when the update loop falls behind. Any time beyond that is
+	// dropped so that a long frame does not cause the game to spiral.
+	MaxCatchUpTicks int
+
+	scenes       []*Scene
+	transition   *sceneTransition
+	window       engineWindow
+	currentFps   atomic.Int64
+	currentTps   atomic.Int64
+	ticks        uint64
+	tickedAt     atomic.Int64
+	alpha        float64
+	timeScale    atomic.Uint64
+	paused       atomic.Bool
+	pendingTicks atomic.Int64
+
+	headlessBackend   *softwarebackend.SoftwareBackend
+	pendingWindowSize atomic.Pointer[Dimensions]
+
+	// canvasTransform is the transform that the engine has applied to the
+	// canvas while rendering, and canvasTransforms are the transforms that
+	// were saved along with the state of the canvas.
+	canvasTransform  Transform
+	canvasTransforms []Transform
+
+	// stateMux guards the scene and everything in it. It is held by the update
+	// loop for the duration of each tick, by the render loop for the duration
+	// of each frame and while input events are dispatched, so none of them
+	// ever observe the game in a partially updated state.
+	stateMux sync.Mutex
+
+	// scenesMux guards the scene stack and the transition in progress, so
+	// that they can be looked up from any goroutine. They are only changed
+	// while the engine is locked, so code holding the lock can read them
+	// directly.
+	scenesMux sync.RWMutex
+
+	// pendingSceneChanges are the changes to the scene stack that were made
+	// while the engine was locked. They are applied when it is unlocked.
+	// locked is true while the engine is locked, and both are guarded by
+	// pendingSceneMux so that a change is never added after the last one has
+	// been applied.
+	pendingSceneChanges []func()
+	locked              bool
+	pendingSceneMux     sync.Mutex
+
+	runMux sync.Mutex
+	cancel context.CancelFunc
 }
 
 var runningEngines []*Engine = []*Engine{}
+var runningEnginesMux sync.Mutex
 
-// NewEngine creates a new engine with the given name and dimensions.
+// NewEngine creates a new engine with the given name and dimensions. It panics
+// if the window can not be created, use NewEngineE to handle that error.
 func NewEngine(name string, dimensions Dimensions) *Engine {
-	engine := Engine{
-		Name:       name,
-		Dimensions: dimensions,
-		MaxTPS:     60,
-		renderMux:  sync.Mutex{},
-	}
-
-	sdl.SetHint(sdl.HINT_VIDEO_HIGHDPI_DISABLED, "1")
-	wnd, cv, err := sdlcanvas.CreateWindow(int(engine.Dimensions.Width), int(engine.Dimensions.Height), engine.Name)
+	engine, err := NewEngineE(name, dimensions)
 	if err != nil {
 		panic(err)
 	}
 
-	wnd.Window.SetResizable(false)
-
-	engine.window = wnd
-	engine.Canvas = cv
-
-	engine.window.KeyDown = func(scancode int, rn rune, name string) {
-		engine.scene.notifyKeyDown(scancode, rn, name)
-	}
-
-	engine.window.KeyUp = func(scancode int, rn rune, name string) {
-		engine.scene.notifyKeyUp(scancode, rn, name)
-	}
+	return engine
+}
 
-	engine.window.MouseUp = func(b, x, y int) {
-		engine.scene.notifyMouseUp(b, Vector{X: float64(x), Y: float64(y)})
+// NewEngineE creates a new engine with the given name and dimensions. An error
+// is returned if the window can not be created, for example when there is no
+// display available. Programs built with the nosdl build tag do not link SDL,
+// so ErrWindowUnsupported is returned and only headless engines can be
+// created.
+func NewEngineE(name string, dimensions Dimensions) (*Engine, error) {
+	engine := Engine{
+		Name:            name,
+		Dimensions:      dimensions,
+		MaxTPS:          60,
+		MaxCatchUpTicks: 5,
 	}
+	engine.SetTimeScale(1)
 
-	engine.window.MouseDown = func(b, x, y int) {
-		engine.scene.notifyMouseDown(b, Vector{X: float64(x), Y: float64(y)})
+	wnd, cv, err := createWindow(&engine)
+	if err != nil {
+		return nil, err
 	}
 
-	engine.window.MouseMove = func(x, y int) {
-		engine.scene.notifyMouseMove(Vector{X: float64(x), Y: float64(y)})
-	}
+	engine.window = wnd
+	engine.Canvas = cv
 
-	return &engine
+	return &engine, nil
 }
 
-// Bounds returns the bounds of the game window.
+// Bounds returns the bounds of the logical resolution of the game.
 func (this *Engine) Bounds() Rect {
 	return Rect{
 		Vector: Vector{
@@ -99,130 +153,476 @@ func (this *Engine) Bounds() Rect {
 
 // GetFPS returns the current FPS.
 func (this *Engine) GetFPS() int {
-	return this.currentFps
+	return int(this.currentFps.Load())
 }
 
 // GetTPS returns the current TPS.
 func (this *Engine) GetTPS() int {
-	return this.currentTps
+	return int(this.currentTps.Load())
 }
 
-// SetScene sets the current scene to the given scene.
-func (this *Engine) SetScene(scene *Scene) {
-	this.renderMux.Lock()
+// defaultTickDuration is the duration of a tick of an engine running at 60
+// TPS, which is used when the MaxTPS of an engine is not set.
+const defaultTickDuration = time.Second / 60
 
-	if this.scene != nil {
-		this.scene.ClearResources()
+// GetTickDuration returns the fixed amount of time that each tick simulates.
+func (this *Engine) GetTickDuration() time.Duration {
+	if this.MaxTPS <= 0 {
+		return defaultTickDuration
+	}
+
+	return time.Second / time.Duration(this.MaxTPS)
+}
+
+// GetInterpolationAlpha returns how far, between 0 and 1, the frame that is
+// currently being rendered is between the previous tick and the next one.
+// Renderers can use it to blend between the previous and current state of an
+// entity so that motion stays smooth when the FPS and TPS differ.
+func (this *Engine) GetInterpolationAlpha() float64 {
+	return this.alpha
+}
+
+// ReportRenderError reports an error that occurred while rendering to the
+// RenderErrorHandler of the engine. If the engine has no RenderErrorHandler,
+// ReportRenderError panics with the error.
+func (this *Engine) ReportRenderError(err error) {
+	if this.RenderErrorHandler == nil {
+		panic(err)
 	}
-	this.scene = scene
-	if this.scene.Initializer != nil {
-		this.scene.Initializer.Initialize(this, this.scene)
+
+	this.RenderErrorHandler.OnRenderError(this, err)
+}
+
+// Pause stops the engine from updating the current scene. The scene is still
+// rendered and still receives input while the engine is paused.
+func (this *Engine) Pause() {
+	this.paused.Store(true)
+}
+
+// Resume continues updating the current scene after a call to Pause.
+func (this *Engine) Resume() {
+	this.pendingTicks.Store(0)
+	this.paused.Store(false)
+}
+
+// IsPaused returns true if the engine is paused.
+func (this *Engine) IsPaused() bool {
+	return this.paused.Load()
+}
+
+// StepTicks advances a paused engine by the given number of ticks. This can be
+// used to implement a frame advance while debugging. It has no effect when the
+// engine is not paused.
+func (this *Engine) StepTicks(n int) {
+	if this.IsPaused() && n > 0 {
+		this.pendingTicks.Add(int64(n))
 	}
-	this.window.Window.SetTitle(fmt.Sprintf("%s - %s", this.Name, this.scene.Name))
+}
 
-	this.renderMux.Unlock()
+// SetTimeScale sets the rate at which game time passes relative to real time.
+// A scale of 0.5 runs the game in slow motion at half speed, while a scale of
+// 2 runs it at double speed. Rendering is not affected by the time scale.
+func (this *Engine) SetTimeScale(scale float64) {
+	this.timeScale.Store(math.Float64bits(scale))
+}
+
+// GetTimeScale returns the rate at which game time passes relative to real
+// time.
+func (this *Engine) GetTimeScale() float64 {
+	return math.Float64frombits(this.timeScale.Load())
+}
+
+// SetScene replaces every scene in the scene stack with the given scene. When
+// called from within an update, render or input handler of the engine, the
+// switch is deferred until that handler has returned so that the current
+// scene is not replaced while it is still being processed. When called from
+// another goroutine while the engine is updating or rendering, the switch
+// takes effect asynchronously once that tick or frame has finished, so the
+// new scene may not be current yet when SetScene returns.
+func (this *Engine) SetScene(scene *Scene) {
+	this.changeScenes(func() {
+		this.finishTransition()
+		for len(this.scenes) > 0 {
+			this.exitScene(this.popScene())
+		}
+		this.enterScene(scene)
+		this.updateTitle()
+	})
 }
 
-// GetScene returns the current scene.
+// GetScene returns the current scene, which is the scene at the top of the
+// scene stack.
 func (this *Engine) GetScene() *Scene {
-	return this.scene
+	this.scenesMux.RLock()
+	defer this.scenesMux.RUnlock()
+
+	if len(this.scenes) == 0 {
+		return nil
+	}
+
+	return this.scenes[len(this.scenes)-1]
 }
 
-// Run starts the game loop.
+// Run starts the game loop. It blocks until Stop is called or the window is
+// closed.
 func (this *Engine) Run() {
-	if this.HideCursor == true {
-		sdl.ShowCursor(0)
+	this.RunContext(context.Background())
+}
+
+// RunContext starts the game loop. It blocks until the given context is done,
+// Stop is called or the window is closed. Once it returns, the update loop has
+// stopped, every scene has been exited and removed from the scene stack and
+// the engine is no longer returned by GetActiveEngines. A headless engine can
+// be run again afterwards by setting a scene again, while the window of a
+// windowed engine is destroyed.
+func (this *Engine) RunContext(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	this.runMux.Lock()
+	if this.cancel != nil {
+		this.runMux.Unlock()
+		return
 	}
-	runningEngines = append(runningEngines, this)
-	this.runUpdates()
-	this.runGraphics()
+	this.cancel = cancel
+	this.runMux.Unlock()
+
+	if this.HideCursor == true && this.window != nil {
+		this.window.hideCursor()
+	}
+	this.register()
+	updatesStopped := this.runUpdates(ctx)
+	this.runGraphics(ctx)
+
+	cancel()
+	<-updatesStopped
+	this.shutdown()
 }
 
-// GetActiveEngine returns the active engine.
+// Stop stops an engine that was started with Run or RunContext. It does not
+// wait for the engine to stop, so it is safe to call from within the update,
+// render and input handlers of the engine. An engine that is being driven
+// manually with Step and RenderFrame is removed from the active engines.
+func (this *Engine) Stop() {
+	this.runMux.Lock()
+	defer this.runMux.Unlock()
+
+	if this.cancel != nil {
+		this.cancel()
+	} else {
+		this.unregister()
+	}
+}
+
+// shutdown exits every scene in the scene stack and releases the engine once
+// its loops have stopped.
+func (this *Engine) shutdown() {
+	this.lock()
+	this.finishTransition()
+	for len(this.scenes) > 0 {
+		this.exitScene(this.popScene())
+	}
+	this.unlock()
+
+	if this.window != nil {
+		this.window.destroy()
+	}
+
+	this.tickedAt.Store(0)
+	this.unregister()
+
+	this.runMux.Lock()
+	this.cancel = nil
+	this.runMux.Unlock()
+}
+
+// GetActiveEngine returns the active engine. It panics if more than one engine
+// is running, use GetActiveEngineE to handle that error.
 func GetActiveEngine() *Engine {
+	engine, err := GetActiveEngineE()
+	if err != nil {
+		panic("More than one running Engine, please use GetActiveEngines() instead.")
+	}
+
+	return engine
+}
+
+// GetActiveEngineE returns the active engine. If no engine is running, nil is
+// returned. If more than one engine is running, ErrMultipleEngines is returned.
+func GetActiveEngineE() (*Engine, error) {
+	runningEnginesMux.Lock()
+	defer runningEnginesMux.Unlock()
+
 	runningEngineCount := len(runningEngines)
 
 	if runningEngineCount < 1 {
-		return nil
+		return nil, nil
 	}
 
 	if runningEngineCount > 1 {
-		panic("More than one running Engine, please use GetActiveEngines() instead.")
+		return nil, ErrMultipleEngines
 	}
 
-	return runningEngines[0]
+	return runningEngines[0], nil
 }
 
 // GetActiveEngines returns all active engines.
 func GetActiveEngines() []*Engine {
-	return runningEngines
+	runningEnginesMux.Lock()
+	defer runningEnginesMux.Unlock()
+
+	return append([]*Engine{}, runningEngines...)
+}
+
+// register adds the engine to the list of running engines if it is not
+// already in it.
+func (this *Engine) register() {
+	runningEnginesMux.Lock()
+	defer runningEnginesMux.Unlock()
+
+	for _, e := range runningEngines {
+		if e == this {
+			return
+		}
+	}
+
+	runningEngines = append(runningEngines, this)
 }
 
-func (this *Engine) runGraphics() {
-	defer this.window.Destroy()
+// unregister removes the engine from the list of running engines.
+func (this *Engine) unregister() {
+	runningEnginesMux.Lock()
+	defer runningEnginesMux.Unlock()
 
+	for i, e := range runningEngines {
+		if e == this {
+			runningEngines = append(runningEngines[:i], runningEngines[i+1:]...)
+			return
+		}
+	}
+}
+
+func (this *Engine) runGraphics(ctx context.Context) {
 	timeSliceOpened := time.Now()
 	framesThisSecond := 0
 
-	this.window.MainLoop(func() {
+	frame := func() {
 		this.render()
 		framesThisSecond += 1
 
 		if time.Since(timeSliceOpened) >= time.Second {
-			this.currentFps = framesThisSecond
+			this.currentFps.Store(int64(framesThisSecond))
 			timeSliceOpened = time.Now()
 			framesThisSecond = 0
 
 			if this.FPSUpdateHandler != nil {
-				this.FPSUpdateHandler.OnFPSUpdated(this, this.currentFps)
+				this.FPSUpdateHandler.OnFPSUpdated(this, this.GetFPS())
 			}
 		}
+	}
+
+	if this.window == nil {
+		this.runHeadlessGraphics(ctx, frame)
+		return
+	}
+
+	this.window.mainLoop(func() {
+		if ctx.Err() != nil {
+			this.window.close()
+			return
+		}
+
+		frame()
 	})
 }
 
-func (this *Engine) runUpdates() {
+// runUpdates starts the update loop on its own goroutine. The returned channel
+// is closed once the loop has stopped after the context is done.
+func (this *Engine) runUpdates(ctx context.Context) <-chan struct{} {
+	stopped := make(chan struct{})
+
 	go func() {
+		defer close(stopped)
+
 		now := time.Now()
 
 		ticksThisSecond := 0
 		timeSliceOpened := now
-		lastTickCompletedAt := time.Now()
+		lastLoopAt := now
+		accumulator := time.Duration(0)
 
-		minimumTickDuration := time.Second / time.Duration(60)
-
-		for {
+		for ctx.Err() == nil {
 			now := time.Now()
+			tickDuration := this.GetTickDuration()
 
-			if time.Since(timeSliceOpened) >= time.Second {
-				this.currentTps = ticksThisSecond
+			if now.Sub(timeSliceOpened) >= time.Second {
+				this.currentTps.Store(int64(ticksThisSecond))
 				ticksThisSecond = 0
 				timeSliceOpened = now
 
 				if this.TPSUpdateHandler != nil {
-					this.TPSUpdateHandler.OnTPSUpdated(this, this.currentTps)
+					this.TPSUpdateHandler.OnTPSUpdated(this, this.GetTPS())
 				}
 			}
 
-			this.update()
+			elapsed := now.Sub(lastLoopAt)
+			lastLoopAt = now
+
+			scale := this.GetTimeScale()
+			if this.IsPaused() || scale <= 0 {
+				accumulator = 0
+				for n := this.pendingTicks.Swap(0); n > 0; n-- {
+					this.update()
+					ticksThisSecond += 1
+				}
+
+				sleepContext(ctx, tickDuration)
+				continue
+			}
+
+			accumulator += time.Duration(float64(elapsed) * scale)
+
+			maxCatchUpTicks := this.MaxCatchUpTicks
+			if maxCatchUpTicks < 1 {
+				maxCatchUpTicks = 1
+			}
+			if accumulator > tickDuration*time.Duration(maxCatchUpTicks) {
+				accumulator = tickDuration * time.Duration(maxCatchUpTicks)
+			}
+
+			for accumulator >= tickDuration {
+				this.update()
+				accumulator -= tickDuration
+				ticksThisSecond += 1
+			}
+
+			// The state of the game now represents the point in time that is
+			// the remainder of the accumulator behind the current time.
+			this.tickedAt.Store(now.Add(-time.Duration(float64(accumulator) / scale)).UnixNano())
 
-			lastTickCompletedAt = now
-			ticksThisSecond += 1
-			timeRemainingForTick := lastTickCompletedAt.Add(minimumTickDuration).Sub(now)
-			time.Sleep(timeRemainingForTick)
+			sleepContext(ctx, time.Duration(float64(tickDuration-accumulator)/scale))
 		}
 	}()
+
+	return stopped
 }
 
 func (this *Engine) update() {
-	this.scene.performUpdate(this)
+	this.lock()
+	defer this.unlock()
+
+	this.ticks += 1
+	for _, scene := range this.updatingScenes() {
+		scene.performUpdate(this)
+	}
+
+	this.advanceTransition()
 }
 
 func (this *Engine) render() {
-	this.renderMux.Lock()
+	this.lock()
+	defer this.unlock()
+
+	if size := this.pendingWindowSize.Swap(nil); size != nil && this.headlessBackend != nil {
+		this.headlessBackend.SetSize(int(size.Width), int(size.Height))
+		this.resized()
+	}
+
+	this.alpha = this.nextInterpolationAlpha()
 
 	w, h := float64(this.Canvas.Width()), float64(this.Canvas.Height())
+	this.setCanvasTransform(NewIdentityTransform())
+	this.canvasTransforms = this.canvasTransforms[:0]
 	this.Canvas.SetFillStyle("#000")
 	this.Canvas.FillRect(0, 0, w, h)
-	this.scene.performRender(this)
-	this.renderMux.Unlock()
+
+	this.saveCanvas()
+	this.applyViewport()
+	this.renderScenes()
+	this.restoreCanvas()
+}
+
+// saveCanvas saves the state of the canvas along with its transform.
+func (this *Engine) saveCanvas() {
+	this.Canvas.Save()
+	this.canvasTransforms = append(this.canvasTransforms, this.canvasTransform)
+}
+
+// restoreCanvas restores the state of the canvas and its transform to what
+// they were when saveCanvas was last called.
+func (this *Engine) restoreCanvas() {
+	this.Canvas.Restore()
+	last := len(this.canvasTransforms) - 1
+	this.canvasTransform, this.canvasTransforms = this.canvasTransforms[last], this.canvasTransforms[:last]
+}
+
+// transformCanvas applies the given transform to the canvas before its
+// current transform.
+func (this *Engine) transformCanvas(t Transform) {
+	this.Canvas.Transform(t.A, t.B, t.C, t.D, t.E, t.F)
+	this.canvasTransform = t.Then(this.canvasTransform)
+}
+
+// setCanvasTransform replaces the transform of the canvas.
+func (this *Engine) setCanvasTransform(t Transform) {
+	this.Canvas.SetTransform(t.A, t.B, t.C, t.D, t.E, t.F)
+	this.canvasTransform = t
+}
+
+// withScene calls fn with the current scene while holding the engine lock. If
+// there is no current scene, fn is not called.
+func (this *Engine) withScene(fn func(scene *Scene)) {
+	this.lock()
+	defer this.unlock()
+
+	if scene := this.GetScene(); scene != nil {
+		fn(scene)
+	}
+}
+
+func (this *Engine) lock() {
+	this.stateMux.Lock()
+
+	this.pendingSceneMux.Lock()
+	this.locked = true
+	this.pendingSceneMux.Unlock()
+}
+
+// unlock applies any changes to the scene stack that were made while the
+// engine was locked and then releases the lock.
+func (this *Engine) unlock() {
+	for change := this.nextSceneChange(); change != nil; change = this.nextSceneChange() {
+		change()
+	}
+
+	this.stateMux.Unlock()
+}
+
+// nextInterpolationAlpha calculates the interpolation alpha for a frame that
+// is rendered now. When the engine is being driven manually or is paused there
+// is no next tick to move towards, so the latest state is always rendered.
+func (this *Engine) nextInterpolationAlpha() float64 {
+	tickedAt := this.tickedAt.Load()
+	scale := this.GetTimeScale()
+	if tickedAt == 0 || this.IsPaused() || scale <= 0 {
+		return 1
+	}
+
+	elapsed := time.Duration(float64(time.Since(time.Unix(0, tickedAt))) * scale)
+	return math.Max(0, math.Min(1, float64(elapsed)/float64(this.GetTickDuration())))
+}
+
+// sleepContext pauses the current goroutine for the given duration or until the
+// context is done, whichever happens first. It returns false if the context is
+// done.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
 }
@@ -0,0 +1,76 @@
+package go2d
+
+import (
+	"context"
+	"image"
+	"image/draw"
+	"time"
+
+	"github.com/tfriedel6/canvas"
+	"github.com/tfriedel6/canvas/backend/softwarebackend"
+)
+
+// headlessFrameDuration is the time between frames when a headless engine is
+// started with Run, since there is no display to synchronize with.
+const headlessFrameDuration = time.Second / 60
+
+// NewHeadlessEngine creates a new engine with the given name and dimensions
+// that renders into an off-screen software canvas instead of a window. A
+// headless engine does not require SDL or a display, which makes it suitable
+// for tests and build servers. It can either be started with Run, or driven
+// manually using Step and RenderFrame.
+func NewHeadlessEngine(name string, dimensions Dimensions) *Engine {
+	backend := softwarebackend.New(int(dimensions.Width), int(dimensions.Height))
+
+	engine := &Engine{
+		Name:            name,
+		Dimensions:      dimensions,
+		MaxTPS:          60,
+		MaxCatchUpTicks: 5,
+		Canvas:          canvas.New(backend),
+		headlessBackend: backend,
+	}
+	engine.SetTimeScale(1)
+
+	return engine
+}
+
+// IsHeadless returns true if the engine renders without a window.
+func (this *Engine) IsHeadless() bool {
+	return this.window == nil
+}
+
+// Step performs a single update of the current scene. This is intended to be
+// used to drive a headless engine manually, without calling Run.
+func (this *Engine) Step() {
+	this.register()
+	this.update()
+}
+
+// RenderFrame renders a single frame of the current scene to the canvas. This
+// is intended to be used to drive a headless engine manually, without calling
+// Run.
+func (this *Engine) RenderFrame() {
+	this.register()
+	this.render()
+}
+
+// GetFrame returns a copy of the pixels that are currently on the canvas.
+func (this *Engine) GetFrame() *image.RGBA {
+	this.lock()
+	defer this.unlock()
+
+	w, h := this.Canvas.Size()
+	pixels := this.Canvas.GetImageData(0, 0, w, h)
+	frame := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(frame, frame.Bounds(), pixels, pixels.Bounds().Min, draw.Src)
+
+	return frame
+}
+
+func (this *Engine) runHeadlessGraphics(ctx context.Context, frame func()) {
+	for ctx.Err() == nil {
+		frame()
+		sleepContext(ctx, headlessFrameDuration)
+	}
+}
@@ -0,0 +1,249 @@
+package go2d
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingUpdater struct {
+	ticks *atomic.Int64
+}
+
+func (this countingUpdater) Update(engine *Engine, scene *Scene) {
+	this.ticks.Add(1)
+}
+
+func newCountingScene(engine *Engine, name string, ticks *atomic.Int64) *Scene {
+	scene := NewScene(engine, name)
+	scene.Updater = countingUpdater{ticks: ticks}
+	scene.AddEntity(0, NewRectImageEntity("#ff0000", Dimensions{Width: 16, Height: 16}))
+	return &scene
+}
+
+// runEngine runs the engine on its own goroutine until the returned function
+// is called, which waits for RunContext to return.
+func runEngine(t *testing.T, engine *Engine) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		engine.RunContext(ctx)
+	}()
+
+	return func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("RunContext did not return after its context was cancelled")
+		}
+	}
+}
+
+func waitFor(t *testing.T, what string, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %v", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestEngineControlsWhileRunning(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	engine.MaxTPS = 240
+
+	var ticks atomic.Int64
+	engine.SetScene(newCountingScene(engine, "main", &ticks))
+	stop := runEngine(t, engine)
+	defer stop()
+
+	waitFor(t, "the first tick", func() bool { return ticks.Load() > 0 })
+
+	var wg sync.WaitGroup
+	control := func(fn func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				fn(i)
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+
+	control(func(i int) {
+		engine.Pause()
+		engine.StepTicks(2)
+		engine.Resume()
+	})
+	control(func(i int) {
+		engine.SetTimeScale(float64(i%4) * 0.5)
+	})
+	control(func(i int) {
+		engine.SetWindowSize(Dimensions{Width: float64(32 + i%3*16), Height: 64})
+	})
+	control(func(i int) {
+		if frame := engine.GetFrame(); frame == nil {
+			t.Error("GetFrame returned nil")
+		}
+	})
+	control(func(i int) {
+		if engine.GetScene() == nil || len(engine.GetScenes()) == 0 {
+			t.Error("scene stack is empty")
+		}
+		engine.GetFPS()
+		engine.GetTPS()
+		engine.IsPaused()
+	})
+	wg.Wait()
+
+	engine.SetTimeScale(1)
+	before := ticks.Load()
+	waitFor(t, "ticks after the controls stopped", func() bool { return ticks.Load() > before })
+}
+
+func TestEngineStepTicksWhilePaused(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+
+	var ticks atomic.Int64
+	engine.SetScene(newCountingScene(engine, "main", &ticks))
+	engine.Pause()
+	stop := runEngine(t, engine)
+	defer stop()
+
+	time.Sleep(50 * time.Millisecond)
+	if n := ticks.Load(); n != 0 {
+		t.Fatalf("paused engine ticked %v times", n)
+	}
+
+	engine.StepTicks(3)
+	waitFor(t, "three stepped ticks", func() bool { return ticks.Load() == 3 })
+
+	time.Sleep(50 * time.Millisecond)
+	if n := ticks.Load(); n != 3 {
+		t.Errorf("paused engine ticked %v times after stepping 3 ticks", n)
+	}
+}
+
+func TestEngineChangesScenesWhileRunning(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	engine.MaxTPS = 240
+
+	var ticks atomic.Int64
+	base := newCountingScene(engine, "base", &ticks)
+	engine.SetScene(base)
+	stop := runEngine(t, engine)
+	defer stop()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			engine.PushScene(newCountingScene(engine, "overlay", &ticks), NewCrossFadeTransition(5*time.Millisecond))
+			time.Sleep(time.Millisecond)
+			engine.PopScene(nil)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			for _, scene := range engine.GetScenes() {
+				if scene == nil {
+					t.Error("scene stack contains nil")
+				}
+			}
+			engine.GetScene()
+			engine.IsTransitioning()
+		}
+	}()
+	wg.Wait()
+
+	waitFor(t, "the overlays to be popped", func() bool { return engine.GetScene() == base })
+}
+
+func TestEngineAppliesSceneChangesMadeWhileItIsLocked(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	defer engine.Stop()
+
+	var ticks atomic.Int64
+	engine.SetScene(newCountingScene(engine, "base", &ticks))
+
+	const pushers, pushes = 8, 50
+	var wg sync.WaitGroup
+	wg.Add(pushers)
+	for i := 0; i < pushers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < pushes; j++ {
+				scene := NewScene(engine, "overlay")
+				engine.PushScene(&scene, nil)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for stepping := true; stepping; {
+		select {
+		case <-done:
+			stepping = false
+		default:
+			engine.Step()
+		}
+	}
+
+	// Every change was applied by the time the call that made it returned or
+	// the engine was unlocked, without waiting for another tick.
+	if got, want := len(engine.GetScenes()), 1+pushers*pushes; got != want {
+		t.Errorf("the scene stack has %v scenes, want %v", got, want)
+	}
+}
+
+type exitCounter struct {
+	exits *atomic.Int64
+}
+
+func (this exitCounter) OnExit(engine *Engine, scene *Scene) {
+	this.exits.Add(1)
+}
+
+func TestEngineRunsAgainAfterStop(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+
+	var ticks, exits atomic.Int64
+	first := newCountingScene(engine, "first", &ticks)
+	first.ExitHandler = exitCounter{exits: &exits}
+	engine.SetScene(first)
+	stop := runEngine(t, engine)
+	waitFor(t, "the first run to tick", func() bool { return ticks.Load() > 0 })
+	stop()
+
+	if n := exits.Load(); n != 1 {
+		t.Errorf("scene was exited %v times, want 1", n)
+	}
+	if scene := engine.GetScene(); scene != nil {
+		t.Errorf("exited scene %q is still in the scene stack", scene.Name)
+	}
+	if len(GetActiveEngines()) != 0 {
+		t.Errorf("stopped engine is still active")
+	}
+
+	engine.SetScene(newCountingScene(engine, "second", &ticks))
+	before := ticks.Load()
+	stop = runEngine(t, engine)
+	waitFor(t, "the second run to tick", func() bool { return ticks.Load() > before })
+	stop()
+
+	if n := exits.Load(); n != 1 {
+		t.Errorf("first scene was exited %v times after the second run, want 1", n)
+	}
+}
@@ -0,0 +1,226 @@
+package go2d
+
+import (
+	"math"
+)
+
+// ScaleMode is a type that represents the different ways the logical
+// resolution of an engine can be scaled to fit its window.
+type ScaleMode int
+
+const (
+	// ScaleModeLetterbox scales the logical resolution as large as possible
+	// while keeping the aspect ratio of the engine, filling the remaining space
+	// with bars.
+	ScaleModeLetterbox = iota
+	// ScaleModeStretch scales the logical resolution to fill the entire window,
+	// ignoring the aspect ratio.
+	ScaleModeStretch
+	// ScaleModeInteger scales the logical resolution by the largest whole
+	// number that fits within the window, keeping pixel art crisp.
+	ScaleModeInteger
+)
+
+// engineWindow is the window that a windowed engine renders into. It is
+// implemented by the SDL window in programs built with the sdl build tag.
+type engineWindow interface {
+	setTitle(title string)
+	setSize(size Dimensions)
+	setResizable(resizable bool)
+	setFullscreen(fullscreen bool) error
+	setBorderless(borderless bool)
+	hideCursor()
+	// mainLoop calls frame for every frame until the window is closed.
+	mainLoop(frame func())
+	close()
+	destroy()
+}
+
+// Viewport describes where the logical resolution of an engine is drawn
+// within its window.
+type Viewport struct {
+	// Offset is the position of the top left corner of the logical resolution
+	// in the window.
+	Offset Vector
+	// Scale is the size of one logical pixel in window pixels.
+	Scale Vector
+}
+
+// GetWindowSize returns the size of the window in pixels. For a headless
+// engine this is the size of the off-screen canvas.
+func (this *Engine) GetWindowSize() Dimensions {
+	w, h := this.Canvas.Size()
+	return Dimensions{
+		Width:  float64(w),
+		Height: float64(h),
+	}
+}
+
+// SetWindowSize resizes the window. The new size takes effect with the next
+// frame, at which point the current scene is notified of the resize.
+func (this *Engine) SetWindowSize(size Dimensions) {
+	if this.window == nil {
+		this.pendingWindowSize.Store(&size)
+		return
+	}
+
+	this.window.setSize(size)
+}
+
+// SetResizable sets whether the window can be resized by the user.
+func (this *Engine) SetResizable(resizable bool) {
+	if this.window != nil {
+		this.window.setResizable(resizable)
+	}
+}
+
+// SetFullscreen switches the window between fullscreen at the resolution of
+// the desktop and windowed mode.
+func (this *Engine) SetFullscreen(fullscreen bool) error {
+	if this.window == nil {
+		return nil
+	}
+
+	return this.window.setFullscreen(fullscreen)
+}
+
+// SetBorderless sets whether the window is drawn without a border and title
+// bar.
+func (this *Engine) SetBorderless(borderless bool) {
+	if this.window != nil {
+		this.window.setBorderless(borderless)
+	}
+}
+
+// GetViewport returns where the logical resolution of the engine is drawn
+// within the window based on the ScaleMode of the engine.
+func (this *Engine) GetViewport() Viewport {
+	window := this.GetWindowSize()
+	if this.Dimensions.Width <= 0 || this.Dimensions.Height <= 0 {
+		return Viewport{Scale: Vector{X: 1, Y: 1}}
+	}
+
+	var size Dimensions
+	switch this.ScaleMode {
+	case ScaleModeStretch:
+		size = window
+	case ScaleModeInteger:
+		scale := math.Floor(math.Min(
+			window.Width/this.Dimensions.Width,
+			window.Height/this.Dimensions.Height,
+		))
+		scale = math.Max(1, scale)
+		size = Dimensions{
+			Width:  this.Dimensions.Width * scale,
+			Height: this.Dimensions.Height * scale,
+		}
+	default:
+		aspectRatio := this.AspectRatio
+		if aspectRatio == nil {
+			aspectRatio = NewAspectRatio(
+				this.Dimensions.Width, this.Dimensions.Height,
+				AspectRatioControlAxisWidth,
+			)
+		}
+		size = aspectRatio.Fit(window)
+	}
+
+	return Viewport{
+		Offset: Vector{
+			X: math.Floor((window.Width - size.Width) / 2),
+			Y: math.Floor((window.Height - size.Height) / 2),
+		},
+		Scale: Vector{
+			X: size.Width / this.Dimensions.Width,
+			Y: size.Height / this.Dimensions.Height,
+		},
+	}
+}
+
+// WindowToLogical converts a position in window pixels to a position in the
+// logical resolution of the engine.
+func (this *Engine) WindowToLogical(pos Vector) Vector {
+	viewport := this.GetViewport()
+	return Vector{
+		X: (pos.X - viewport.Offset.X) / viewport.Scale.X,
+		Y: (pos.Y - viewport.Offset.Y) / viewport.Scale.Y,
+	}
+}
+
+// LogicalToWindow converts a position in the logical resolution of the engine
+// to a position in window pixels.
+func (this *Engine) LogicalToWindow(pos Vector) Vector {
+	viewport := this.GetViewport()
+	return Vector{
+		X: pos.X*viewport.Scale.X + viewport.Offset.X,
+		Y: pos.Y*viewport.Scale.Y + viewport.Offset.Y,
+	}
+}
+
+// applyViewport transforms the canvas so that drawing in the logical
+// resolution of the engine ends up in the right place in the window. Anything
+// drawn outside of the logical resolution is clipped so that it does not
+// bleed into the bars around it. The engine must be locked.
+func (this *Engine) applyViewport() {
+	viewport := this.GetViewport()
+	window := this.GetWindowSize()
+
+	this.setCanvasTransform(Transform{
+		A: viewport.Scale.X, D: viewport.Scale.Y,
+		E: viewport.Offset.X, F: viewport.Offset.Y,
+	})
+
+	if viewport.Offset.X > 0 || viewport.Offset.Y > 0 ||
+		this.Dimensions.Width*viewport.Scale.X < window.Width ||
+		this.Dimensions.Height*viewport.Scale.Y < window.Height {
+		this.Canvas.BeginPath()
+		this.Canvas.Rect(0, 0, this.Dimensions.Width, this.Dimensions.Height)
+		this.Canvas.Clip()
+	}
+}
+
+// keyDown notifies the current scene that a key was pressed.
+func (this *Engine) keyDown(scancode int, rn rune, name string) {
+	this.withScene(func(scene *Scene) {
+		scene.notifyKeyDown(scancode, rn, name)
+	})
+}
+
+// keyUp notifies the current scene that a key was released.
+func (this *Engine) keyUp(scancode int, rn rune, name string) {
+	this.withScene(func(scene *Scene) {
+		scene.notifyKeyUp(scancode, rn, name)
+	})
+}
+
+// mouseDown notifies the current scene that a mouse button was pressed at the
+// given position in window pixels.
+func (this *Engine) mouseDown(button int, x int, y int) {
+	this.withScene(func(scene *Scene) {
+		scene.notifyMouseDown(button, this.WindowToLogical(Vector{X: float64(x), Y: float64(y)}))
+	})
+}
+
+// mouseUp notifies the current scene that a mouse button was released at the
+// given position in window pixels.
+func (this *Engine) mouseUp(button int, x int, y int) {
+	this.withScene(func(scene *Scene) {
+		scene.notifyMouseUp(button, this.WindowToLogical(Vector{X: float64(x), Y: float64(y)}))
+	})
+}
+
+// mouseMove notifies the current scene that the mouse moved to the given
+// position in window pixels.
+func (this *Engine) mouseMove(x int, y int) {
+	this.withScene(func(scene *Scene) {
+		scene.notifyMouseMove(this.WindowToLogical(Vector{X: float64(x), Y: float64(y)}))
+	})
+}
+
+// resized notifies every scene in the scene stack that the window has been
+// resized. The engine must be locked.
+func (this *Engine) resized() {
+	for _, scene := range this.scenes {
+		scene.notifyResize(this, this.GetWindowSize())
+	}
+}
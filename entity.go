@@ -1,5 +1,11 @@
 package go2d
 
+import (
+	"image/color"
+	"math"
+	"time"
+)
+
 // IEntityRenderer is an interface that can be implemented by entities that
 // want to render themselves.
 type IEntityRenderer interface {
@@ -31,13 +37,42 @@ type IEntityCollider interface {
 	GetCollider() Rect
 }
 
+// IEntityShapeCollider is an interface that can be implemented by entities
+// whose collider is not an axis aligned rectangle. It is used instead of
+// IEntityCollider when an entity implements both.
+type IEntityShapeCollider interface {
+	GetShape() IShape
+}
+
 // IEntityCollisionDetection is an interface that can be implemented by
 // entities that want to be notified when they collide with other
-// entities that implement IEntityCollider.
+// entities that implement IEntityCollider or IEntityShapeCollider.
 type IEntityCollisionDetection interface {
 	CollidedWith(other interface{})
 }
 
+// IEntityCollisionEnterHandler is an interface that can be implemented by
+// entities that want to be notified on the first tick that they collide with
+// another entity that implements IEntityCollider.
+type IEntityCollisionEnterHandler interface {
+	OnCollisionEnter(other interface{})
+}
+
+// IEntityCollisionStayHandler is an interface that can be implemented by
+// entities that want to be notified on every tick after the first that they
+// are still colliding with another entity that implements IEntityCollider.
+type IEntityCollisionStayHandler interface {
+	OnCollisionStay(other interface{})
+}
+
+// IEntityCollisionExitHandler is an interface that can be implemented by
+// entities that want to be notified on the first tick that they are no longer
+// colliding with another entity that implements IEntityCollider, including
+// when the other entity is removed from the scene.
+type IEntityCollisionExitHandler interface {
+	OnCollisionExit(other interface{})
+}
+
 type IEntity interface {
 	GetEntity() *Entity
 }
@@ -50,6 +85,40 @@ type Entity struct {
 	Bounds Rect
 	// Velocity is the entity's velocity.
 	Velocity VelocityVector
+	// Rotation is the clockwise rotation of the entity around its origin in
+	// radians.
+	Rotation float64
+	// Scale is the horizontal and vertical scale of the entity around its
+	// origin. A zero scale is treated as a scale of 1, so that entities are
+	// drawn at their normal size by default.
+	Scale Vector
+	// Origin is the point, relative to the top left corner of the entity,
+	// that the entity is rotated, scaled and flipped around.
+	Origin Vector
+	// FlipX mirrors the entity horizontally around its origin.
+	FlipX bool
+	// FlipY mirrors the entity vertically around its origin.
+	FlipY bool
+	// Alpha is the opacity of the entity, from 0 for fully transparent to 1
+	// for fully opaque. It is multiplied with the alpha of the entity's
+	// parents. The New functions create entities with an alpha of 1, so
+	// entities that are created without them should set it to be drawn.
+	Alpha float64
+	// Tint is the color that the colors of the entity are multiplied with
+	// when it is drawn, or nil to draw the entity in its own colors. It is
+	// multiplied with the tint of the entity's parents. Images are tinted by
+	// copying them whenever the tint changes, which is slow for large images.
+	Tint color.Color
+	// BlendMode is how the entity is blended with what has already been drawn
+	// behind it. Entities with BlendModeNormal use the blend mode of their
+	// parent. The other blend modes are composited in software within the
+	// bounds of the entity, so they are much slower than BlendModeNormal.
+	BlendMode BlendMode
+
+	parent           *EntityGroup
+	previous         Vector
+	previousRotation float64
+	previousTick     uint64
 }
 
 // CollidesWith returns true if the entity collides with the other entity. This
@@ -62,6 +131,7 @@ func (this *Entity) CollidesWith(other *Entity) bool {
 // MoveTo moves the entity to the specified position instantly.
 func (this *Entity) MoveTo(pos Vector) {
 	this.Bounds.Vector = pos
+	this.previous = pos
 }
 
 // Push moves the entity by the specified distance instantly.
@@ -70,7 +140,239 @@ func (this *Entity) Push(distance Vector) {
 	this.Bounds.Vector.Y += distance.Y
 }
 
-// Update updates the entity's position based on its velocity.
+// Update updates the entity's position based on its velocity, using the tick
+// duration of the engine updating the scene that the entity is in.
 func (this *Entity) Update() {
-	this.Push(this.Velocity.GetNextMovement())
+	this.Push(this.Velocity.GetMovement(this.sceneTickDuration()))
+}
+
+// sceneTickDuration returns the duration of the tick that the scene the
+// entity is in is being updated for, or that of an engine running at 60 TPS
+// if it is not in a scene.
+func (this *Entity) sceneTickDuration() time.Duration {
+	root := this.parent
+	for root != nil && !root.sceneRoot {
+		root = root.parent
+	}
+
+	if root == nil || root.tickDuration <= 0 {
+		return defaultTickDuration
+	}
+
+	return root.tickDuration
+}
+
+// CenterOrigin sets the origin of the entity to the center of its bounds, so
+// that it rotates, scales and flips around its center.
+func (this *Entity) CenterOrigin() {
+	this.Origin = Vector{
+		X: this.Bounds.Width / 2,
+		Y: this.Bounds.Height / 2,
+	}
+}
+
+// GetScale returns the scale of the entity, treating a zero scale as a scale
+// of 1.
+func (this *Entity) GetScale() Vector {
+	if this.Scale.IsZero() {
+		return Vector{X: 1, Y: 1}
+	}
+
+	return this.Scale
+}
+
+// RenderBounds returns the bounds that the entity should be drawn at in the
+// frame that is currently being rendered. The position is interpolated between
+// where the entity was at the start of the last tick and where it is now using
+// the engine's interpolation alpha.
+func (this *Entity) RenderBounds(e *Engine) Rect {
+	bounds := this.Bounds
+	if this.previousTick == e.ticks {
+		bounds.Vector = this.previous.Lerp(bounds.Vector, e.GetInterpolationAlpha())
+	}
+
+	return bounds
+}
+
+// RenderRotation returns the rotation that the entity should be drawn with in
+// the frame that is currently being rendered, interpolated in the same way as
+// RenderBounds.
+func (this *Entity) RenderRotation(e *Engine) float64 {
+	if this.previousTick == e.ticks {
+		return this.previousRotation + (this.Rotation-this.previousRotation)*e.GetInterpolationAlpha()
+	}
+
+	return this.Rotation
+}
+
+// ApplyTransform applies the rotation, scale and flipping of the entity to
+// the canvas, and translates the canvas so that the top left corner of the
+// entity, at the given bounds, is drawn at 0, 0. Callers should save the
+// state of the canvas before calling it and restore it afterwards.
+func (this *Entity) ApplyTransform(e *Engine, bounds Rect) {
+	t := this.renderTransform(e, bounds)
+	e.Canvas.Transform(t.A, t.B, t.C, t.D, t.E, t.F)
+}
+
+// renderTransform returns the transform that ApplyTransform applies.
+func (this *Entity) renderTransform(e *Engine, bounds Rect) Transform {
+	return this.transform(bounds.Vector, this.RenderRotation(e))
+}
+
+// Draw calls draw with the canvas of the engine transformed so that the top
+// left corner of the entity, at the given bounds, is drawn at 0, 0 and with
+// the alpha and blend mode of the entity applied. Nothing is drawn if the
+// entity is fully transparent. Tints are not applied, since they depend on
+// what is being drawn. Entities that are not drawn with BlendModeNormal are
+// clipped to their bounds.
+func (this *Entity) Draw(e *Engine, bounds Rect, draw func()) {
+	this.drawIn(e, bounds, NewRect(0, 0, bounds.Width, bounds.Height), draw)
+}
+
+// drawIn works like Draw for entities that draw outside of their bounds,
+// where area is the part of the canvas that draw draws in, relative to the
+// top left corner of the entity.
+func (this *Entity) drawIn(e *Engine, bounds Rect, area Rect, draw func()) {
+	alpha := this.WorldAlpha()
+	if alpha <= 0 {
+		return
+	}
+
+	mode := this.WorldBlendMode()
+	if mode == BlendModeNormal {
+		e.Canvas.Save()
+		this.ApplyTransform(e, bounds)
+		e.Canvas.SetGlobalAlpha(math.Min(alpha, 1))
+		draw()
+		e.Canvas.Restore()
+		return
+	}
+
+	transform := this.renderTransform(e, bounds)
+	blend(e, mode, transform.Then(e.canvasTransform).ApplyRect(area), func() {
+		e.Canvas.Save()
+		e.Canvas.Transform(transform.A, transform.B, transform.C, transform.D, transform.E, transform.F)
+		e.Canvas.BeginPath()
+		e.Canvas.Rect(area.X, area.Y, area.Width, area.Height)
+		e.Canvas.Clip()
+		e.Canvas.SetGlobalAlpha(math.Min(alpha, 1))
+		draw()
+		e.Canvas.Restore()
+	})
+}
+
+// WorldAlpha returns the opacity of the entity, from 0 for fully transparent
+// to 1 for fully opaque, multiplied with the opacity of all of its parents.
+func (this *Entity) WorldAlpha() float64 {
+	alpha := this.opacity()
+	for parent := this.parent; parent != nil; parent = parent.parent {
+		alpha *= parent.opacity()
+	}
+
+	return alpha
+}
+
+// opacity returns the opacity of the entity on its own.
+func (this *Entity) opacity() float64 {
+	return math.Max(0, math.Min(this.Alpha, 1))
+}
+
+// opacity returns the opacity of the entity group on its own, including how
+// far its scene is faded out while it is rendered if it is the root of a
+// scene.
+func (this *EntityGroup) opacity() float64 {
+	return this.Entity.opacity() * math.Max(0, math.Min(1-this.renderFade, 1))
+}
+
+// WorldTint returns the tint of the entity multiplied with the tint of all of
+// its parents, or nil if none of them are tinted.
+func (this *Entity) WorldTint() color.Color {
+	tint := this.Tint
+	for parent := this.parent; parent != nil; parent = parent.parent {
+		tint = multiplyColors(tint, parent.Tint)
+	}
+
+	return tint
+}
+
+// imageTint returns the tint that images of the entity should be drawn with.
+// The software backend that headless engines render with ignores the global
+// alpha of the canvas when drawing images, so there the alpha of the entity
+// is part of the tint instead.
+func (this *Entity) imageTint(e *Engine) color.Color {
+	tint := this.WorldTint()
+	if alpha := this.WorldAlpha(); e.IsHeadless() && alpha < 1 {
+		tint = multiplyColors(tint, color.NRGBA{
+			R: 255, G: 255, B: 255, A: uint8(math.Max(alpha, 0) * 255),
+		})
+	}
+
+	return tint
+}
+
+// WorldBlendMode returns the blend mode of the entity, or that of the closest
+// of its parents that does not use BlendModeNormal.
+func (this *Entity) WorldBlendMode() BlendMode {
+	mode := this.BlendMode
+	for parent := this.parent; parent != nil && mode == BlendModeNormal; parent = parent.parent {
+		mode = parent.BlendMode
+	}
+
+	return mode
+}
+
+// Parent returns the entity group that the entity was added to, or nil if it
+// has not been added to a group. Entities added directly to a scene return
+// the entity group of the scene.
+func (this *Entity) Parent() *EntityGroup {
+	return this.parent
+}
+
+// LocalTransform returns the transform from the space of the entity, where
+// its top left corner is at 0, 0, to the space of its parent.
+func (this *Entity) LocalTransform() Transform {
+	return this.transform(this.Bounds.Vector, this.Rotation)
+}
+
+// WorldTransform returns the transform from the space of the entity, where
+// its top left corner is at 0, 0, to the space of its scene, combining the
+// transforms of all of its parents.
+func (this *Entity) WorldTransform() Transform {
+	t := this.LocalTransform()
+	for parent := this.parent; parent != nil && !parent.sceneRoot; parent = parent.parent {
+		t = t.Then(parent.LocalTransform())
+	}
+
+	return t
+}
+
+// WorldBounds returns the smallest axis aligned rectangle that contains the
+// entity in the space of its scene.
+func (this *Entity) WorldBounds() Rect {
+	return this.WorldTransform().ApplyRect(NewZeroRect(this.Bounds.Width, this.Bounds.Height))
+}
+
+// transform returns the transform of the entity as if it were at the given
+// position with the given rotation.
+func (this *Entity) transform(position Vector, rotation float64) Transform {
+	scale := this.GetScale()
+	if this.FlipX {
+		scale.X = -scale.X
+	}
+	if this.FlipY {
+		scale.Y = -scale.Y
+	}
+
+	return NewTranslateTransform(this.Origin.Inverted()).
+		Then(NewScaleTransform(scale)).
+		Then(NewRotateTransform(rotation)).
+		Then(NewTranslateTransform(position.Add(this.Origin)))
+}
+
+// snapshot records the current position and rotation of the entity as they
+// were at the start of the given tick.
+func (this *Entity) snapshot(tick uint64) {
+	this.previous = this.Bounds.Vector
+	this.previousRotation = this.Rotation
+	this.previousTick = tick
 }
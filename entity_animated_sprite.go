@@ -0,0 +1,292 @@
+package go2d
+
+import (
+	"image"
+	"time"
+)
+
+// AnimationMode is a type that represents the different ways an animation
+// clip can be played back.
+type AnimationMode int
+
+const (
+	// AnimationModeLoop plays the frames of the clip in order and starts over
+	// from the first frame once the last frame has been shown.
+	AnimationModeLoop = iota
+	// AnimationModePingPong plays the frames of the clip forwards and then
+	// backwards, over and over.
+	AnimationModePingPong
+	// AnimationModeOnce plays the frames of the clip once and then stops on
+	// the last frame.
+	AnimationModeOnce
+)
+
+// IAnimationCompleteHandler is an interface that can be implemented to be
+// notified when an AnimatedSpriteEntity completes a clip. For looping clips
+// this is called each time the clip starts over.
+type IAnimationCompleteHandler interface {
+	OnAnimationComplete(entity *AnimatedSpriteEntity, clip string)
+}
+
+// AnimationClip is a sequence of frames that can be played by an
+// AnimatedSpriteEntity.
+type AnimationClip struct {
+	// Frames are the images that make up the clip.
+	Frames []image.Image
+	// Durations are the durations of each individual frame. Frames without a
+	// duration use FrameDuration.
+	Durations []time.Duration
+	// FrameDuration is the duration of frames that do not have their own
+	// duration in Durations.
+	FrameDuration time.Duration
+	// Mode is how the clip is played back.
+	Mode AnimationMode
+}
+
+// NewAnimationClip creates a new animation clip from the given frames, each
+// shown for the given duration.
+func NewAnimationClip(frames []image.Image, frameDuration time.Duration, mode AnimationMode) *AnimationClip {
+	return &AnimationClip{
+		Frames:        frames,
+		FrameDuration: frameDuration,
+		Mode:          mode,
+	}
+}
+
+// NewSpriteSheetClip creates a new animation clip from count consecutive
+// sprites of the given sprite sheet, starting at the given location and
+// continuing on the next row when the end of a row is reached.
+func NewSpriteSheetClip(sheet *SpriteSheet, start Vector, count int, frameDuration time.Duration, mode AnimationMode) (*AnimationClip, error) {
+	frames, err := sheet.GetSprites(start, count)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAnimationClip(frames, frameDuration, mode), nil
+}
+
+// GetFrameDuration returns the duration of the frame at the given index.
+func (this *AnimationClip) GetFrameDuration(frame int) time.Duration {
+	if frame < len(this.Durations) {
+		return this.Durations[frame]
+	}
+
+	return this.FrameDuration
+}
+
+// AnimatedSpriteEntity is an entity that draws the frames of named animation
+// clips. The animation is advanced by the engine's tick duration each time
+// the entity is updated, so it stays in sync with the rest of the game.
+type AnimatedSpriteEntity struct {
+	Entity
+
+	// Speed is the playback speed of the animation. A speed of 2 plays the
+	// animation twice as fast.
+	Speed float64
+	// CompleteHandler is the handler that will be called when a clip is
+	// completed.
+	CompleteHandler IAnimationCompleteHandler
+
+	clips     map[string]*AnimationClip
+	clip      string
+	frame     int
+	direction int
+	elapsed   time.Duration
+	playing   bool
+	tinted    tintedImages
+	loadErr   error
+}
+
+// NewAnimatedSpriteEntity creates a new animated sprite entity without any
+// clips.
+func NewAnimatedSpriteEntity() *AnimatedSpriteEntity {
+	return &AnimatedSpriteEntity{
+		Entity: Entity{
+			Visible: true,
+			Alpha:   1,
+		},
+		Speed:     1,
+		clips:     map[string]*AnimationClip{},
+		direction: 1,
+	}
+}
+
+// AddClip adds a clip with the given name to the entity.
+func (this *AnimatedSpriteEntity) AddClip(name string, clip *AnimationClip) {
+	this.clips[name] = clip
+}
+
+// GetClip returns the clip with the given name.
+func (this *AnimatedSpriteEntity) GetClip(name string) *AnimationClip {
+	return this.clips[name]
+}
+
+// Play starts playing the clip with the given name from its first frame. If
+// the clip is already playing, it keeps playing from where it is. If the
+// entity has no size yet, it is sized to the first frame of the clip.
+func (this *AnimatedSpriteEntity) Play(name string) {
+	clip := this.clips[name]
+	if clip == nil || (this.clip == name && this.playing) {
+		return
+	}
+
+	this.clip = name
+	this.Restart()
+
+	if this.Bounds.Dimensions == NewZeroDimensions() && len(clip.Frames) > 0 {
+		this.Bounds.Dimensions = Dimensions{
+			Width:  float64(clip.Frames[0].Bounds().Dx()),
+			Height: float64(clip.Frames[0].Bounds().Dy()),
+		}
+	}
+}
+
+// Restart plays the current clip again from its first frame.
+func (this *AnimatedSpriteEntity) Restart() {
+	this.frame = 0
+	this.direction = 1
+	this.elapsed = 0
+	this.playing = true
+}
+
+// Stop stops the animation on the current frame.
+func (this *AnimatedSpriteEntity) Stop() {
+	this.playing = false
+}
+
+// Resume continues playing the animation from the current frame.
+func (this *AnimatedSpriteEntity) Resume() {
+	if this.clips[this.clip] != nil {
+		this.playing = true
+	}
+}
+
+// IsPlaying returns true if the animation is playing.
+func (this *AnimatedSpriteEntity) IsPlaying() bool {
+	return this.playing
+}
+
+// GetCurrentClip returns the name of the current clip.
+func (this *AnimatedSpriteEntity) GetCurrentClip() string {
+	return this.clip
+}
+
+// GetCurrentFrame returns the index of the current frame in the current clip.
+func (this *AnimatedSpriteEntity) GetCurrentFrame() int {
+	return this.frame
+}
+
+// SetCurrentFrame jumps to the frame at the given index in the current clip.
+func (this *AnimatedSpriteEntity) SetCurrentFrame(frame int) {
+	clip := this.clips[this.clip]
+	if clip != nil && frame >= 0 && frame < len(clip.Frames) {
+		this.frame = frame
+		this.elapsed = 0
+	}
+}
+
+// GetImage returns the image of the current frame, or nil if no clip is
+// selected.
+func (this *AnimatedSpriteEntity) GetImage() image.Image {
+	clip := this.clips[this.clip]
+	if clip == nil || len(clip.Frames) == 0 {
+		return nil
+	}
+
+	return clip.Frames[this.frame]
+}
+
+// Advance moves the animation forward by the given amount of time, scaled by
+// the speed of the entity.
+func (this *AnimatedSpriteEntity) Advance(d time.Duration) {
+	clip := this.clips[this.clip]
+	if clip == nil || len(clip.Frames) == 0 || !this.playing || this.Speed <= 0 {
+		return
+	}
+
+	this.elapsed += time.Duration(float64(d) * this.Speed)
+	for this.playing {
+		frameDuration := clip.GetFrameDuration(this.frame)
+		if frameDuration <= 0 || this.elapsed < frameDuration {
+			break
+		}
+
+		this.elapsed -= frameDuration
+		this.nextFrame(clip)
+	}
+}
+
+func (this *AnimatedSpriteEntity) nextFrame(clip *AnimationClip) {
+	count := len(clip.Frames)
+
+	switch clip.Mode {
+	case AnimationModeOnce:
+		if this.frame+1 >= count {
+			this.playing = false
+			this.elapsed = 0
+			this.complete()
+			return
+		}
+		this.frame += 1
+	case AnimationModePingPong:
+		if count == 1 {
+			this.complete()
+			return
+		}
+		if this.frame+this.direction < 0 || this.frame+this.direction >= count {
+			this.direction = -this.direction
+		}
+		this.frame += this.direction
+		if this.frame == 0 {
+			this.complete()
+		}
+	default:
+		this.frame += 1
+		if this.frame >= count {
+			this.frame = 0
+			this.complete()
+		}
+	}
+}
+
+func (this *AnimatedSpriteEntity) complete() {
+	if this.CompleteHandler != nil {
+		this.CompleteHandler.OnAnimationComplete(this, this.clip)
+	}
+}
+
+// Render renders the current frame of the animated sprite entity.
+func (this *AnimatedSpriteEntity) Render(e *Engine) {
+	img := this.GetImage()
+	if img == nil || !this.Visible || this.loadErr != nil {
+		return
+	}
+
+	if tint := this.imageTint(e); tint != nil {
+		img = this.tinted.get(img, tint)
+	}
+
+	cImg, err := e.Canvas.LoadImage(img)
+	if err != nil {
+		this.loadErr = err
+		e.ReportRenderError(err)
+		return
+	}
+
+	bounds := this.RenderBounds(e)
+	this.Draw(e, bounds, func() {
+		e.Canvas.DrawImage(cImg, 0, 0, bounds.Width, bounds.Height)
+	})
+}
+
+// Update updates the position of the animated sprite entity and advances its
+// animation by one tick.
+func (this *AnimatedSpriteEntity) Update(e *Engine) {
+	this.Entity.Update()
+	this.Advance(e.GetTickDuration())
+}
+
+// GetEntity returns the entity of the animated sprite entity.
+func (this *AnimatedSpriteEntity) GetEntity() *Entity {
+	return &this.Entity
+}
@@ -5,15 +5,39 @@ import (
 	"math/rand"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// entitiesAdded counts the entities that have been added to entity groups, so
+// that entities can be ordered by when they were added.
+var entitiesAdded atomic.Uint64
+
+// entitiesChanged counts the times that entities have been added to or
+// removed from entity groups, so that the colliders of a scene can be
+// collected again for queries after they changed.
+var entitiesChanged atomic.Uint64
+
+// entityKey is the layer and key that an entity was added to a group with.
+type entityKey struct {
+	layer int
+	key   interface{}
+}
+
 // EntityGroup is an entity that represents a group of
 // entities that can be rendered in layers.
 type EntityGroup struct {
 	Entity
 
-	entities *sync.Map
+	entities  *sync.Map
+	added     *sync.Map
+	sceneRoot bool
+	// tickDuration is the duration of the tick that the scene is being
+	// updated for, which is only set on the root of a scene.
+	tickDuration time.Duration
+	// renderFade is how far the scene is faded out while it is being
+	// rendered, which is only set on the root of a scene.
+	renderFade float64
 }
 
 type byLayer []int
@@ -25,7 +49,11 @@ func (a byLayer) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 // NewEntityGroup creates a new entity group.
 func NewEntityGroup() *EntityGroup {
 	return &EntityGroup{
+		Entity: Entity{
+			Alpha: 1,
+		},
 		entities: &sync.Map{},
+		added:    &sync.Map{},
 	}
 }
 
@@ -34,17 +62,33 @@ func (this *EntityGroup) AddEntity(layer int, ent interface{}) string {
 	n := time.Now().UnixNano()
 	r := rand.New(rand.NewSource(n))
 	id := fmt.Sprintf("entity_%v.%v", n, r.Intn(10000))
-
-	layerData, _ := this.entities.LoadOrStore(layer, &sync.Map{})
-	(layerData.(*sync.Map)).LoadOrStore(id, ent)
+	this.store(layer, id, ent)
 
 	return id
 }
 
 // AddNamedEntity adds an entity to the group with the given name.
 func (this *EntityGroup) AddNamedEntity(name string, layer int, ent interface{}) {
+	this.store(layer, name, ent)
+}
+
+// store adds an entity to a layer of the group with the given key, unless
+// there already is an entity with that key, and records when it was added.
+func (this *EntityGroup) store(layer int, key string, ent interface{}) {
 	layerData, _ := this.entities.LoadOrStore(layer, &sync.Map{})
-	(layerData.(*sync.Map)).LoadOrStore(name, ent)
+	if _, loaded := (layerData.(*sync.Map)).LoadOrStore(key, ent); !loaded {
+		this.added.Store(entityKey{layer: layer, key: key}, entitiesAdded.Add(1))
+		entitiesChanged.Add(1)
+		this.adopt(ent)
+	}
+}
+
+// addedAt returns the order in which the entity with the given layer and key
+// was added to the group, compared to every other entity in any group.
+func (this *EntityGroup) addedAt(layer int, key interface{}) uint64 {
+	added, _ := this.added.Load(entityKey{layer: layer, key: key})
+	order, _ := added.(uint64)
+	return order
 }
 
 // GetEntity gets an entity from the group.
@@ -57,19 +101,60 @@ func (this *EntityGroup) GetEntity(layer int, name string) interface{} {
 // RemoveEntity removes an entity from the group.
 func (this *EntityGroup) RemoveEntity(layer int, name string) {
 	layerData, _ := this.entities.LoadOrStore(layer, &sync.Map{})
-	(layerData.(*sync.Map)).Delete(name)
+	if ent, loaded := (layerData.(*sync.Map)).LoadAndDelete(name); loaded {
+		this.added.Delete(entityKey{layer: layer, key: name})
+		entitiesChanged.Add(1)
+		this.orphan(ent)
+	}
 }
 
 // ClearEntities clears all entities from the group.
 func (this *EntityGroup) ClearEntities() {
+	this.IterateEntities(this.orphan)
+	entitiesChanged.Add(1)
 	this.entities.Range(func(key, value interface{}) bool {
 		this.entities.Delete(key)
 		return true
 	})
+	this.added.Range(func(key, value interface{}) bool {
+		this.added.Delete(key)
+		return true
+	})
+}
+
+// Children returns the entities in the group in the order they are rendered.
+func (this *EntityGroup) Children() []interface{} {
+	children := []interface{}{}
+	this.IterateEntities(func(e interface{}) {
+		children = append(children, e)
+	})
+
+	return children
+}
+
+// adopt makes the group the parent of the given entity.
+func (this *EntityGroup) adopt(ent interface{}) {
+	if entity, isEntity := entityOf(ent); isEntity {
+		entity.parent = this
+	}
+}
+
+// orphan removes the given entity from the group's children.
+func (this *EntityGroup) orphan(ent interface{}) {
+	if entity, isEntity := entityOf(ent); isEntity && entity.parent == this {
+		entity.parent = nil
+	}
 }
 
 // IterateEntities iterates over all entities in the group.
 func (this *EntityGroup) IterateEntities(cb func(interface{})) {
+	for _, layer := range this.layers() {
+		this.iterateLayer(layer, cb)
+	}
+}
+
+// layers returns the layers of the group in the order they are rendered.
+func (this *EntityGroup) layers() []int {
 	layers := []int{}
 	this.entities.Range(func(key, value interface{}) bool {
 		layers = append(layers, key.(int))
@@ -77,43 +162,165 @@ func (this *EntityGroup) IterateEntities(cb func(interface{})) {
 	})
 	sort.Sort(byLayer(layers))
 
-	for i := 0; i < len(layers); i++ {
-		layer := layers[i]
-		entities, _ := this.entities.Load(layer)
-		if entities != nil {
-			entities.(*sync.Map).Range(func(key, value interface{}) bool {
-				cb(value)
-				return true
-			})
-		}
+	return layers
+}
+
+// iterateLayer iterates over all entities in a single layer of the group.
+func (this *EntityGroup) iterateLayer(layer int, cb func(interface{})) {
+	entities, _ := this.entities.Load(layer)
+	if entities != nil {
+		entities.(*sync.Map).Range(func(key, value interface{}) bool {
+			cb(value)
+			return true
+		})
 	}
 }
 
-// Render renders the entity group.
+// Render renders the entity group. The entities in the group are positioned
+// relative to the group by transforming the canvas, so their bounds are never
+// modified while rendering and the rotation, scale and flipping of the group
+// are applied on top of their own.
 func (this *EntityGroup) Render(engine *Engine) {
+	engine.saveCanvas()
+	engine.transformCanvas(this.renderTransform(engine, this.RenderBounds(engine)))
+
+	for _, layer := range this.layers() {
+		this.renderLayer(engine, layer)
+	}
+
+	engine.restoreCanvas()
+}
+
+// renderLayer renders the entities in a single layer of the group.
+func (this *EntityGroup) renderLayer(engine *Engine, layer int) {
+	this.iterateLayer(layer, func(e interface{}) {
+		_, isEntity := entityOf(e)
+		_, isRenderable := e.(IEntityRenderer)
+		if isEntity && isRenderable {
+			e.(IEntityRenderer).Render(engine)
+		}
+	})
+}
+
+// Update updates the entity group, then constrains and updates each of the
+// entities in the group. Types that embed an EntityGroup and implement their
+// own Update should call this to keep updating their children.
+func (this *EntityGroup) Update(engine *Engine) {
+	this.Entity.Update()
+	this.updateChildren(engine)
+}
+
+// updateChildren constrains and updates each of the entities in the group.
+// Nested entity groups update their own children when they are updated.
+func (this *EntityGroup) updateChildren(engine *Engine) {
 	this.IterateEntities(func(e interface{}) {
-		_, isEntity := e.(IEntity)
-		if isEntity {
-			entity := e.(IEntity).GetEntity()
-			originalLoc := entity.Bounds.Vector
-			entity.Bounds.Vector = Vector{
-				X: this.Bounds.Vector.X + originalLoc.X,
-				Y: this.Bounds.Vector.Y + originalLoc.Y,
+		_, isConstrain := e.(IEntityConstraint)
+		if isConstrain {
+			constrainedSides := e.(IEntityConstraint).Constrain(engine)
+			_, isConstrained := e.(IEntityConstrainedHandler)
+			if isConstrained {
+				for _, side := range constrainedSides {
+					e.(IEntityConstrainedHandler).OnConstrained(side)
+				}
 			}
+		}
 
-			_, isRenderable := e.(IEntityRenderer)
-			if isRenderable {
-				e.(IEntityRenderer).Render(engine)
+		_, isUpdatable := e.(IEntityUpdater)
+		if isUpdatable {
+			e.(IEntityUpdater).Update(engine)
+		}
+	})
+}
+
+// walkEntities calls cb for every entity in the group and, recursively, in
+// the entity groups inside of it.
+func (this *EntityGroup) walkEntities(cb func(interface{})) {
+	this.walkEntitiesIn(func(group *EntityGroup, layer int, key interface{}, e interface{}) {
+		cb(e)
+	})
+}
+
+// walkEntitiesIn calls cb for every entity in the group and, recursively, in
+// the entity groups inside of it, along with the group, layer and key that
+// the entity was added with.
+func (this *EntityGroup) walkEntitiesIn(cb func(group *EntityGroup, layer int, key interface{}, e interface{})) {
+	for _, layer := range this.layers() {
+		entities, _ := this.entities.Load(layer)
+		if entities == nil {
+			continue
+		}
+
+		entities.(*sync.Map).Range(func(key, value interface{}) bool {
+			cb(this, layer, key, value)
+			if group, isGroup := value.(entityGroup); isGroup {
+				group.group().walkEntitiesIn(cb)
 			}
+			return true
+		})
+	}
+}
 
-			entity.Bounds.Vector = originalLoc
+// sceneTransform returns the transform from the space of the group, where its
+// entities are positioned, to the space of its scene. Entities that have not
+// been added to a group are treated as if they were added to a scene.
+func (this *EntityGroup) sceneTransform() Transform {
+	if this == nil || this.sceneRoot {
+		return NewIdentityTransform()
+	}
+
+	return this.WorldTransform()
+}
+
+// walkEntitiesAt calls cb for every entity in the group and, recursively, in
+// the entity groups inside of it, with the given position converted from the
+// space of the group's parent to the space of the entity's parent.
+func (this *EntityGroup) walkEntitiesAt(pos Vector, cb func(interface{}, Vector)) {
+	local := this.LocalTransform().Inverse().Apply(pos)
+	this.IterateEntities(func(e interface{}) {
+		cb(e, local)
+		if group, isGroup := e.(entityGroup); isGroup {
+			group.group().walkEntitiesAt(local, cb)
 		}
 	})
 }
 
-// Update updates the entity group.
-func (this *EntityGroup) Update(engine *Engine) {
-	this.Entity.Update()
+// group returns the entity group, so that types embedding an entity group
+// can be recognized as one.
+func (this *EntityGroup) group() *EntityGroup {
+	return this
+}
+
+// IEntityGroup is an interface implemented by EntityGroup and the types that
+// embed it. Entity groups cannot implement IEntity, because GetEntity looks up
+// the entities inside of them.
+type IEntityGroup interface {
+	GetEntityGroupEntity() *Entity
+}
+
+// newSceneRoot creates the entity group at the root of a scene. Its
+// transform is not part of the world transform of its children.
+func newSceneRoot() *EntityGroup {
+	root := NewEntityGroup()
+	root.sceneRoot = true
+	return root
+}
+
+// entityGroup is implemented by EntityGroup and the types that embed it.
+type entityGroup interface {
+	group() *EntityGroup
+}
+
+// entityOf returns the entity of an entity or entity group.
+func entityOf(e interface{}) (*Entity, bool) {
+	if entity, isEntity := e.(IEntity); isEntity {
+		return entity.GetEntity(), true
+	}
+
+	if group, isGroup := e.(IEntityGroup); isGroup {
+		return group.GetEntityGroupEntity(), true
+	}
+
+	return nil, false
 }
 
 // GetEntityGroupEntity returns the entity group entity.
@@ -18,8 +18,12 @@ type ITexture interface {
 type ImageEntity struct {
 	Entity
 
-	gImg image.Image
-	cImg *canvas.Image
+	gImg       image.Image
+	cImg       *canvas.Image
+	tinted     tintedImages
+	tintedImg  *image.RGBA
+	cTintedImg *canvas.Image
+	loadErr    error
 }
 
 // NewImageEntity creates a new image entity from the given image.
@@ -28,6 +32,7 @@ func NewImageEntity(img image.Image) *ImageEntity {
 		gImg: img,
 		Entity: Entity{
 			Visible: true,
+			Alpha:   1,
 			Bounds: Rect{
 				Dimensions: Dimensions{
 					Width:  float64(img.Bounds().Dx()),
@@ -86,24 +91,47 @@ func (this *ImageEntity) GetImage() image.Image {
 	return this.gImg
 }
 
-// Render renders the image entity.
+// Render renders the image entity. If the image can not be loaded into the
+// canvas, the error is reported to the engine once and the entity is not drawn.
 func (this *ImageEntity) Render(e *Engine) {
+	if this.loadErr != nil {
+		return
+	}
+
 	if this.cImg == nil {
 		i, err := e.Canvas.LoadImage(this.gImg)
 		if err != nil {
-			panic(err)
+			this.loadErr = err
+			e.ReportRenderError(err)
+			return
 		}
 		this.cImg = i
 	}
 
 	if this.Visible {
-		e.Canvas.DrawImage(
-			this.cImg,
-			this.Bounds.X,
-			this.Bounds.Y,
-			this.Bounds.Width,
-			this.Bounds.Height,
-		)
+		img := this.cImg
+		if tint := this.imageTint(e); tint != nil {
+			// The tinted image is only loaded into the canvas again when
+			// the tint changes.
+			if tinted := this.tinted.get(this.gImg, tint); tinted != this.tintedImg {
+				cTinted, err := e.Canvas.LoadImage(tinted)
+				if err != nil {
+					this.loadErr = err
+					e.ReportRenderError(err)
+					return
+				}
+				if this.cTintedImg != nil {
+					this.cTintedImg.Delete()
+				}
+				this.tintedImg, this.cTintedImg = tinted, cTinted
+			}
+			img = this.cTintedImg
+		}
+
+		bounds := this.RenderBounds(e)
+		this.Draw(e, bounds, func() {
+			e.Canvas.DrawImage(img, 0, 0, bounds.Width, bounds.Height)
+		})
 	}
 }
 
@@ -54,6 +54,7 @@ func NewLineEntitySimple(from Vector, to Vector, thickness int, color string) *L
 func NewLineEntity(from Vector, direction Vector, length float64, thickness int, color string) *LineEntity {
 	return &LineEntity{
 		Entity: Entity{
+			Alpha: 1,
 			Bounds: Rect{
 				Vector: from,
 			},
@@ -72,22 +73,20 @@ func (this *LineEntity) SetCapStyle(capStyle LineCapStyle) {
 
 // Render renders the line entity.
 func (this *LineEntity) Render(e *Engine) {
-	e.Canvas.SetLineWidth(float64(this.thickness))
-	e.Canvas.SetStrokeStyle(this.color)
-	this.capStyle.fillLineCapStyle(e.Canvas)
-
-	e.Canvas.BeginPath()
-	e.Canvas.MoveTo(this.Bounds.X, this.Bounds.Y)
-
-	to := Vector{
-		X: this.Bounds.X + (this.direction.X * this.length),
-		Y: this.Bounds.Y + (this.direction.Y * this.length),
-	}
-
-	e.Canvas.LineTo(to.X, to.Y)
-
-	e.Canvas.ClosePath()
-	e.Canvas.Stroke()
+	to := this.direction.Scaled(this.length)
+	area := NewCapsule(Vector{}, to, float64(this.thickness)).GetBounds()
+	this.drawIn(e, this.RenderBounds(e), area, func() {
+		e.Canvas.SetLineWidth(float64(this.thickness))
+		e.Canvas.SetStrokeStyle(tintStyle(this.color, this.WorldTint()))
+		this.capStyle.fillLineCapStyle(e.Canvas)
+
+		e.Canvas.BeginPath()
+		e.Canvas.MoveTo(0, 0)
+		e.Canvas.LineTo(to.X, to.Y)
+
+		e.Canvas.ClosePath()
+		e.Canvas.Stroke()
+	})
 }
 
 // Update updates the line entity.
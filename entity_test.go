@@ -0,0 +1,190 @@
+package go2d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// squareEntity is an entity created with a struct literal that fills its
+// bounds with red.
+type squareEntity struct {
+	Entity
+}
+
+func (this *squareEntity) Render(e *Engine) {
+	bounds := this.RenderBounds(e)
+	this.Draw(e, bounds, func() {
+		e.Canvas.SetFillStyle("#ff0000")
+		e.Canvas.FillRect(0, 0, bounds.Width, bounds.Height)
+	})
+}
+
+func (this *squareEntity) Update(e *Engine) {
+	this.Entity.Update()
+}
+
+func (this *squareEntity) GetEntity() *Entity {
+	return &this.Entity
+}
+
+func TestEntityAlpha(t *testing.T) {
+	tests := []struct {
+		name       string
+		alpha      float64
+		groupAlpha float64
+		red        uint8
+	}{
+		{"opaque", 1, 1, 255},
+		{"half transparent", 0.5, 1, 128},
+		{"half transparent group", 1, 0.5, 128},
+		{"half transparent in a half transparent group", 0.5, 0.5, 64},
+		{"fully transparent", 0, 1, 0},
+		{"fully transparent group", 1, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+			defer engine.Stop()
+			scene := NewScene(engine, "alpha")
+
+			group := NewEntityGroup()
+			group.Alpha = test.groupAlpha
+			scene.AddEntity(0, group)
+
+			square := &squareEntity{Entity{Bounds: NewRect(16, 16, 32, 32), Alpha: 1}}
+			square.Alpha = test.alpha
+			group.AddEntity(0, square)
+
+			engine.SetScene(&scene)
+			engine.RenderFrame()
+
+			pixel := engine.GetFrame().RGBAAt(32, 32)
+			if diff := int(pixel.R) - int(test.red); diff < -2 || diff > 2 {
+				t.Errorf("red = %v, want %v", pixel.R, test.red)
+			}
+		})
+	}
+}
+
+func TestNewEntitiesAreOpaque(t *testing.T) {
+	entities := map[string]*Entity{
+		"entity group":    &NewEntityGroup().Entity,
+		"image":           &NewRectImageEntity("#ff0000", Dimensions{Width: 4, Height: 4}).Entity,
+		"text":            &NewTextEntitySimple("text").Entity,
+		"line":            &NewLineEntitySimple(Vector{}, Vector{X: 10}, 1, "#ff0000").Entity,
+		"animated sprite": &NewAnimatedSpriteEntity().Entity,
+	}
+
+	for name, entity := range entities {
+		if entity.WorldAlpha() != 1 {
+			t.Errorf("%v has an alpha of %v, want 1", name, entity.WorldAlpha())
+		}
+	}
+}
+
+func TestEntityVelocityUsesTheTickDurationOfItsEngine(t *testing.T) {
+	squares := []*squareEntity{}
+	for _, tps := range []int{30, 120} {
+		engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+		defer engine.Stop()
+		engine.MaxTPS = tps
+		scene := NewScene(engine, "velocity")
+
+		group := NewEntityGroup()
+		scene.AddEntity(0, group)
+
+		square := &squareEntity{}
+		square.Velocity = NewVelocityVector(60, 0, time.Second)
+		group.AddEntity(0, square)
+		squares = append(squares, square)
+
+		engine.SetScene(&scene)
+		for i := 0; i < tps; i++ {
+			engine.Step()
+		}
+	}
+
+	for _, square := range squares {
+		if math.Abs(square.Bounds.X-60) > 1e-3 {
+			t.Errorf("entity moved %v in one second, want 60", square.Bounds.X)
+		}
+	}
+}
+
+// filledEntity is an entity that fills its bounds with a color.
+type filledEntity struct {
+	Entity
+	color string
+}
+
+func (this *filledEntity) Render(e *Engine) {
+	bounds := this.RenderBounds(e)
+	this.Draw(e, bounds, func() {
+		e.Canvas.SetFillStyle(this.color)
+		e.Canvas.FillRect(0, 0, bounds.Width, bounds.Height)
+	})
+}
+
+func (this *filledEntity) GetEntity() *Entity {
+	return &this.Entity
+}
+
+func TestEntityBlendModes(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   BlendMode
+		inside [3]uint8
+	}{
+		{"normal", BlendModeNormal, [3]uint8{255, 0, 0}},
+		{"additive", BlendModeAdditive, [3]uint8{255, 128, 128}},
+		{"multiply", BlendModeMultiply, [3]uint8{128, 0, 0}},
+		{"screen", BlendModeScreen, [3]uint8{255, 128, 128}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+			defer engine.Stop()
+			scene := NewScene(engine, "blend")
+
+			background := &filledEntity{Entity{Bounds: NewRect(0, 0, 64, 64), Alpha: 1}, "#808080"}
+			scene.AddNamedEntity("background", 0, background)
+
+			// The group moves the entity, so that it has to be found where
+			// it is drawn on the canvas instead of where its bounds are.
+			group := NewEntityGroup()
+			group.Bounds = NewRect(8, 8, 0, 0)
+			scene.AddNamedEntity("group", 1, group)
+
+			square := &filledEntity{Entity{Bounds: NewRect(8, 8, 32, 32), Alpha: 1, BlendMode: test.mode}, "#ff0000"}
+			group.AddEntity(0, square)
+
+			engine.SetScene(&scene)
+			engine.RenderFrame()
+			frame := engine.GetFrame()
+
+			pixels := []struct {
+				x, y int
+				want [3]uint8
+			}{
+				{32, 32, test.inside},
+				{17, 17, test.inside},
+				{46, 46, test.inside},
+				{14, 32, [3]uint8{128, 128, 128}},
+				{50, 50, [3]uint8{128, 128, 128}},
+				{2, 2, [3]uint8{128, 128, 128}},
+			}
+			for _, pixel := range pixels {
+				got := frame.RGBAAt(pixel.x, pixel.y)
+				for c, value := range []uint8{got.R, got.G, got.B} {
+					if diff := int(value) - int(pixel.want[c]); diff < -2 || diff > 2 {
+						t.Errorf("pixel at %v, %v = %v, want %v", pixel.x, pixel.y, got, pixel.want)
+						break
+					}
+				}
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ type TextEntity struct {
 	textCentering TextCentering
 
 	isMeasured bool
+	fontErr    error
 }
 
 // TextCentering is a type that represents the different ways text can be
@@ -40,6 +41,7 @@ func NewTextEntity(text string, font string, fontSize float64, textColor string)
 		textColor:  textColor,
 		isMeasured: false,
 		Entity: Entity{
+			Alpha: 1,
 			Bounds: Rect{
 				Vector: Vector{
 					X: 0,
@@ -61,6 +63,7 @@ func NewTextEntitySimple(text string) *TextEntity {
 		textColor:  defaultTextColor,
 		isMeasured: false,
 		Entity: Entity{
+			Alpha: 1,
 			Bounds: Rect{
 				Vector: Vector{
 					X: 0,
@@ -106,6 +109,7 @@ func (this *TextEntity) SetFontSize(fontSize float64) {
 func (this *TextEntity) SetFont(font string) {
 	this.font = font
 	this.isMeasured = false
+	this.fontErr = nil
 }
 
 // SetTextColor sets the text color of this text entity.
@@ -116,6 +120,10 @@ func (this *TextEntity) SetTextColor(color string) {
 // Measure updates the bounds of this text entity based on the text, font, and
 // font size also taking into account the centering of the text.
 func (this *TextEntity) Measure(e *Engine) {
+	if !this.loadFont(e) {
+		return
+	}
+
 	e.Canvas.SetFont(this.font, float64(this.fontSize))
 	tm := e.Canvas.MeasureText(this.text)
 	this.Bounds.Dimensions = Dimensions{
@@ -141,13 +149,40 @@ func (this *TextEntity) Measure(e *Engine) {
 
 // Render renders this text entity to the given engine.
 func (this *TextEntity) Render(e *Engine) {
+	if !this.loadFont(e) {
+		return
+	}
+
 	if !this.isMeasured {
 		this.Measure(e)
 	}
 
-	e.Canvas.SetFont(this.font, this.fontSize)
-	e.Canvas.SetFillStyle(this.textColor)
-	e.Canvas.FillText(this.text, this.Bounds.X, this.Bounds.Y+this.Bounds.Height)
+	// The text is drawn on the bottom of its bounds, so the parts of the
+	// letters that go below the baseline are drawn outside of them.
+	bounds := this.RenderBounds(e)
+	area := NewRect(0, 0, bounds.Width, bounds.Height+this.fontSize)
+	this.drawIn(e, bounds, area, func() {
+		e.Canvas.SetFont(this.font, this.fontSize)
+		e.Canvas.SetFillStyle(tintStyle(this.textColor, this.WorldTint()))
+		e.Canvas.FillText(this.text, 0, bounds.Height)
+	})
+}
+
+// loadFont makes sure that the font of this text entity can be loaded by the
+// canvas of the given engine. If it can not, the error is reported to the
+// engine once and false is returned until a different font is set.
+func (this *TextEntity) loadFont(e *Engine) bool {
+	if this.fontErr != nil {
+		return false
+	}
+
+	_, this.fontErr = e.Canvas.LoadFont(this.font)
+	if this.fontErr != nil {
+		e.ReportRenderError(this.fontErr)
+		return false
+	}
+
+	return true
 }
 
 // Update updates this text entity.
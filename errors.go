@@ -0,0 +1,24 @@
+package go2d
+
+import "errors"
+
+// ErrMultipleEngines is returned when a single engine is requested but more
+// than one engine is running.
+var ErrMultipleEngines = errors.New("more than one running Engine, use GetActiveEngines() instead")
+
+// ErrSubImageUnsupported is returned when a sprite is requested from a sprite
+// sheet whose image can not be divided into sub images.
+var ErrSubImageUnsupported = errors.New("sprite sheet image does not support sub images")
+
+// ErrAtlasFrameNotFound is returned when a frame is requested from a texture
+// atlas that does not contain it.
+var ErrAtlasFrameNotFound = errors.New("frame not found in texture atlas")
+
+// ErrAtlasTagNotFound is returned when a frame tag is requested from a texture
+// atlas that does not contain it.
+var ErrAtlasTagNotFound = errors.New("frame tag not found in texture atlas")
+
+// ErrWindowUnsupported is returned when a window is requested from a program
+// that was built with the nosdl build tag. Only headless engines can be
+// created in those programs.
+var ErrWindowUnsupported = errors.New("go2d was built with the nosdl tag, so windows can not be created")
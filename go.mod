@@ -2,11 +2,13 @@ module github.com/nathan-fiscaletti/go2d
 
 go 1.19
 
-require github.com/tfriedel6/canvas v0.12.1
+require (
+	github.com/tfriedel6/canvas v0.12.1
+	github.com/veandco/go-sdl2 v0.4.33
+)
 
 require (
 	github.com/go-gl/gl v0.0.0-20181026044259-55b76b7df9d2 // indirect
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
-	github.com/veandco/go-sdl2 v0.4.33 // indirect
 	golang.org/x/image v0.0.0-20200119044424-58c23975cae1 // indirect
 )
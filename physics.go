@@ -0,0 +1,492 @@
+package go2d
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultPhysicsIterations is the number of times the contacts between rigid
+// bodies are solved each tick by a new physics world.
+const DefaultPhysicsIterations = 8
+
+// PhysicsWorld simulates the rigid bodies of a scene. It is advanced once
+// every tick by the scene that it is attached to.
+type PhysicsWorld struct {
+	// Gravity is the acceleration applied to every dynamic rigid body in
+	// pixels per second squared.
+	Gravity Vector
+	// Iterations is the number of times the contacts between rigid bodies
+	// are solved each tick. More iterations make stacked bodies more stable.
+	Iterations int
+	// Correction is the fraction, between 0 and 1, of the overlap between
+	// rigid bodies that is removed each tick by moving them apart.
+	Correction float64
+}
+
+// NewPhysicsWorld creates a new physics world with the given gravity in
+// pixels per second squared.
+func NewPhysicsWorld(gravity Vector) *PhysicsWorld {
+	return &PhysicsWorld{
+		Gravity:    gravity,
+		Iterations: DefaultPhysicsIterations,
+		Correction: 0.8,
+	}
+}
+
+// IEntityRigidBody is an interface that can be implemented by entities that
+// want to be simulated by the physics world of their scene. The physics world
+// moves these entities itself using the velocity of their rigid body and
+// clears their Velocity, so they do not need an Update method and their
+// Velocity should not be set directly. Rigid bodies that also implement
+// IEntityCollider or IEntityShapeCollider collide with each other and with
+// the entities that implement IEntityBody, which are treated as immovable.
+type IEntityRigidBody interface {
+	IEntity
+	GetRigidBody() *RigidBody
+}
+
+// RigidBody is the physical state of an entity simulated by a physics world.
+type RigidBody struct {
+	// Type is the type of the body. Static bodies never move, kinematic
+	// bodies move with their velocity but are not affected by forces or
+	// collisions and dynamic bodies are fully simulated.
+	Type BodyType
+	// Mass is the mass of the body. A mass of zero is treated as one.
+	Mass float64
+	// Inertia is the rotational inertia of the body. If it is zero, the
+	// physics world calculates it from the mass and the size of the collider
+	// when solving collisions.
+	Inertia float64
+	// Restitution is how bouncy the body is, from 0 to 1.
+	Restitution float64
+	// Friction is how much the body resists sliding along other bodies.
+	Friction float64
+	// GravityScale is multiplied with the gravity of the physics world.
+	GravityScale float64
+	// LinearDamping slows down the linear velocity of the body over time.
+	LinearDamping float64
+	// AngularDamping slows down the angular velocity of the body over time.
+	AngularDamping float64
+	// FixedRotation prevents collisions from rotating the body.
+	FixedRotation bool
+
+	// LinearVelocity is the velocity of the body in pixels per second.
+	LinearVelocity Vector
+	// AngularVelocity is the clockwise angular velocity of the body in
+	// radians per second. It drives the Rotation of the entity, which
+	// colliders that should rotate with the body can use in their shape.
+	AngularVelocity float64
+
+	force  Vector
+	torque float64
+}
+
+// NewRigidBody creates a new rigid body of the given type and mass with a
+// moderate amount of friction and no bounce.
+func NewRigidBody(bodyType BodyType, mass float64) *RigidBody {
+	return &RigidBody{
+		Type:         bodyType,
+		Mass:         mass,
+		Friction:     0.3,
+		GravityScale: 1,
+	}
+}
+
+// ApplyForce applies a force to the center of the body during the next tick.
+func (this *RigidBody) ApplyForce(force Vector) {
+	this.force = this.force.Add(force)
+}
+
+// ApplyTorque applies a clockwise torque to the body during the next tick.
+func (this *RigidBody) ApplyTorque(torque float64) {
+	this.torque += torque
+}
+
+// ApplyImpulse immediately changes the velocity of the body by applying an
+// impulse to its center.
+func (this *RigidBody) ApplyImpulse(impulse Vector) {
+	if this.Type != BodyTypeDynamic {
+		return
+	}
+
+	this.LinearVelocity = this.LinearVelocity.Add(impulse.Scaled(this.inverseMass()))
+}
+
+// ApplyImpulseAt immediately changes the velocity of the body by applying an
+// impulse at the given offset from its center, which may also make it spin.
+// The spin is only applied when the Inertia of the body is set.
+func (this *RigidBody) ApplyImpulseAt(impulse Vector, offset Vector) {
+	if this.Type != BodyTypeDynamic {
+		return
+	}
+
+	this.ApplyImpulse(impulse)
+	this.AngularVelocity += offset.Cross(impulse) * this.inverseInertia()
+}
+
+func (this *RigidBody) inverseMass() float64 {
+	if this.Type != BodyTypeDynamic {
+		return 0
+	}
+
+	if this.Mass <= 0 {
+		return 1
+	}
+
+	return 1 / this.Mass
+}
+
+func (this *RigidBody) inverseInertia() float64 {
+	if this.Type != BodyTypeDynamic || this.FixedRotation || this.Inertia <= 0 {
+		return 0
+	}
+
+	return 1 / this.Inertia
+}
+
+// velocityAt returns the velocity of the point of the body at the given
+// offset from its center.
+func (this *RigidBody) velocityAt(offset Vector) Vector {
+	return this.LinearVelocity.Add(offset.Perpendicular().Scaled(this.AngularVelocity))
+}
+
+// physicsBody is a rigid body that was found while stepping the physics
+// world, or an immovable body for an entity that implements IEntityBody.
+type physicsBody struct {
+	*RigidBody
+	entity *Entity
+	// inertia is the Inertia of the rigid body, or the inertia calculated
+	// from its collider when it is not set.
+	inertia float64
+}
+
+// newPhysicsBody creates the physics body of a rigid body whose collider has
+// the given shape.
+func newPhysicsBody(rigidBody IEntityRigidBody, shape IShape) *physicsBody {
+	body := &physicsBody{
+		RigidBody: rigidBody.GetRigidBody(),
+		entity:    rigidBody.GetEntity(),
+	}
+
+	body.inertia = body.Inertia
+	if body.inertia <= 0 {
+		body.inertia = shapeInertia(shape, body.Mass)
+	}
+
+	return body
+}
+
+func (this *physicsBody) inverseMass() float64 {
+	if this.RigidBody == nil {
+		return 0
+	}
+
+	return this.RigidBody.inverseMass()
+}
+
+// inverseInertia returns the inverse inertia of the body when its collider
+// has the given shape. Axis aligned rectangles cannot rotate, so bodies using
+// them as their collider are never spun by collisions.
+func (this *physicsBody) inverseInertia(shape IShape) float64 {
+	if _, isRect := shape.(Rect); isRect || this.RigidBody == nil {
+		return 0
+	}
+
+	if this.Type != BodyTypeDynamic || this.FixedRotation || this.inertia <= 0 {
+		return 0
+	}
+
+	return 1 / this.inertia
+}
+
+func (this *physicsBody) velocityAt(offset Vector) Vector {
+	if this.RigidBody == nil {
+		return Vector{}
+	}
+
+	return this.RigidBody.velocityAt(offset)
+}
+
+func (this *physicsBody) applyImpulse(impulse Vector, offset Vector, inverseInertia float64) {
+	if this.RigidBody == nil || this.Type != BodyTypeDynamic {
+		return
+	}
+
+	this.LinearVelocity = this.LinearVelocity.Add(impulse.Scaled(this.inverseMass()))
+	this.AngularVelocity += offset.Cross(impulse) * inverseInertia
+}
+
+// physicsContact is a contact between two bodies that is being solved.
+type physicsContact struct {
+	a, b        *physicsBody
+	pair        [2]int
+	inertiaA    float64
+	inertiaB    float64
+	normal      Vector
+	depth       float64
+	offsetA     Vector
+	offsetB     Vector
+	bounce      float64
+	friction    float64
+	normalMass  float64
+	tangentMass float64
+	impulse     float64
+	tangent     float64
+}
+
+// integrate applies the forces, gravity and damping to the velocity of every
+// rigid body in the scene, then moves and rotates their entities by it.
+func (this *PhysicsWorld) integrate(scene *Scene, dt time.Duration) {
+	seconds := dt.Seconds()
+
+	scene.walkEntities(func(e interface{}) {
+		rigidBody, isRigidBody := e.(IEntityRigidBody)
+		if !isRigidBody {
+			return
+		}
+
+		body := rigidBody.GetRigidBody()
+		entity := rigidBody.GetEntity()
+
+		switch body.Type {
+		case BodyTypeDynamic:
+			acceleration := this.Gravity.Scaled(body.GravityScale).Add(body.force.Scaled(body.inverseMass()))
+			body.LinearVelocity = body.LinearVelocity.Add(acceleration.Scaled(seconds))
+			body.LinearVelocity = body.LinearVelocity.Scaled(1 / (1 + seconds*body.LinearDamping))
+			body.AngularVelocity += body.torque * body.inverseInertia() * seconds
+			body.AngularVelocity *= 1 / (1 + seconds*body.AngularDamping)
+		case BodyTypeStatic:
+			body.LinearVelocity = Vector{}
+			body.AngularVelocity = 0
+		}
+
+		body.force = Vector{}
+		body.torque = 0
+		entity.Rotation += body.AngularVelocity * seconds
+		pushWorld(entity, body.LinearVelocity.Scaled(seconds))
+
+		// Entities that call Entity.Update from their own Update would
+		// otherwise be moved a second time.
+		entity.Velocity = VelocityVector{}
+	})
+}
+
+// solve finds the contacts between the rigid bodies and pushes them apart by
+// iteratively applying impulses to them.
+func (this *PhysicsWorld) solve(scene *Scene, colliders []*collider, nearby func(c *collider, cb func(other *collider))) {
+	bodies := map[*collider]*physicsBody{}
+	for _, c := range colliders {
+		if rigidBody, isRigidBody := c.entity.(IEntityRigidBody); isRigidBody {
+			bodies[c] = newPhysicsBody(rigidBody, c.shape)
+		} else if c.body != BodyTypeNone {
+			bodies[c] = &physicsBody{}
+		}
+	}
+
+	contacts := []*physicsContact{}
+	for _, c := range colliders {
+		a, isBody := bodies[c]
+		if !isBody || a.RigidBody == nil {
+			continue
+		}
+
+		nearby(c, func(other *collider) {
+			b, isOtherBody := bodies[other]
+			if !isOtherBody || other == c {
+				return
+			}
+
+			// Pairs of rigid bodies are only solved once.
+			if b.RigidBody != nil && other.index < c.index {
+				return
+			}
+
+			if a.inverseMass() == 0 && b.inverseMass() == 0 {
+				return
+			}
+
+			if !c.interactsWith(other, scene.CollisionMatrix) || !scene.isInScene(c) || !scene.isInScene(other) {
+				return
+			}
+
+			if contact := this.newContact(a, b, c.shape, other.shape); contact != nil {
+				contact.pair = [2]int{c.index, other.index}
+				contacts = append(contacts, contact)
+			}
+		})
+	}
+
+	// The order that contacts are solved in changes the result, so they are
+	// sorted by their colliders instead of the order the broad phase found
+	// them in.
+	sort.SliceStable(contacts, func(i, j int) bool {
+		a, b := contacts[i].pair, contacts[j].pair
+		return a[0] < b[0] || a[0] == b[0] && a[1] < b[1]
+	})
+
+	for i := 0; i < this.Iterations; i++ {
+		for _, contact := range contacts {
+			this.solveContact(contact)
+		}
+	}
+
+	for _, contact := range contacts {
+		this.correct(contact)
+	}
+}
+
+// newContact returns the contact between two bodies if their shapes overlap.
+func (this *PhysicsWorld) newContact(a *physicsBody, b *physicsBody, shapeA IShape, shapeB IShape) *physicsContact {
+	if !shapeA.GetBounds().IntersectsWith(shapeB.GetBounds()) {
+		return nil
+	}
+
+	overlap, intersects := CollideShapes(shapeA, shapeB)
+	if !intersects {
+		return nil
+	}
+
+	centerA, centerB := shapeA.GetBounds().Center(), shapeB.GetBounds().Center()
+	point := contactPoint(shapeA, shapeB, overlap.Normal)
+
+	contact := &physicsContact{
+		a:       a,
+		b:       b,
+		normal:  overlap.Normal,
+		depth:   overlap.Depth,
+		offsetA: point.Sub(centerA),
+		offsetB: point.Sub(centerB),
+	}
+
+	for _, body := range []*physicsBody{a, b} {
+		if body.RigidBody != nil {
+			contact.bounce = math.Max(contact.bounce, body.Restitution)
+		}
+	}
+	contact.friction = math.Sqrt(bodyFriction(a) * bodyFriction(b))
+
+	contact.inertiaA, contact.inertiaB = a.inverseInertia(shapeA), b.inverseInertia(shapeB)
+
+	tangent := contact.normal.Perpendicular()
+	contact.normalMass = effectiveMass(contact, contact.normal)
+	contact.tangentMass = effectiveMass(contact, tangent)
+
+	// Only bounce when the bodies are approaching each other quickly, so
+	// that resting bodies settle instead of jittering.
+	approach := b.velocityAt(contact.offsetB).Sub(a.velocityAt(contact.offsetA)).Dot(contact.normal)
+	if approach < -1 {
+		contact.bounce *= -approach
+	} else {
+		contact.bounce = 0
+	}
+
+	return contact
+}
+
+// solveContact applies the impulses that stop the bodies of the contact from
+// moving into each other and from sliding along each other.
+func (this *PhysicsWorld) solveContact(contact *physicsContact) {
+	a, b := contact.a, contact.b
+
+	relative := b.velocityAt(contact.offsetB).Sub(a.velocityAt(contact.offsetA))
+	impulse := (-relative.Dot(contact.normal) + contact.bounce) * contact.normalMass
+	total := math.Max(contact.impulse+impulse, 0)
+	impulse, contact.impulse = total-contact.impulse, total
+
+	a.applyImpulse(contact.normal.Scaled(-impulse), contact.offsetA, contact.inertiaA)
+	b.applyImpulse(contact.normal.Scaled(impulse), contact.offsetB, contact.inertiaB)
+
+	tangent := contact.normal.Perpendicular()
+	relative = b.velocityAt(contact.offsetB).Sub(a.velocityAt(contact.offsetA))
+	friction := -relative.Dot(tangent) * contact.tangentMass
+	limit := contact.friction * contact.impulse
+	total = math.Max(-limit, math.Min(limit, contact.tangent+friction))
+	friction, contact.tangent = total-contact.tangent, total
+
+	a.applyImpulse(tangent.Scaled(-friction), contact.offsetA, contact.inertiaA)
+	b.applyImpulse(tangent.Scaled(friction), contact.offsetB, contact.inertiaB)
+}
+
+// correct moves the bodies of the contact apart to remove most of the
+// overlap that is left after solving their velocities.
+func (this *PhysicsWorld) correct(contact *physicsContact) {
+	inverseMassA, inverseMassB := contact.a.inverseMass(), contact.b.inverseMass()
+	depth := math.Max(contact.depth-resolutionSlop, 0) * this.Correction / (inverseMassA + inverseMassB)
+
+	if contact.a.RigidBody != nil {
+		pushWorld(contact.a.entity, contact.normal.Scaled(-depth*inverseMassA))
+	}
+	if contact.b.RigidBody != nil {
+		pushWorld(contact.b.entity, contact.normal.Scaled(depth*inverseMassB))
+	}
+}
+
+// effectiveMass returns the inverse of the combined resistance of the bodies
+// of the contact to an impulse along the given direction.
+func effectiveMass(contact *physicsContact, direction Vector) float64 {
+	crossA, crossB := contact.offsetA.Cross(direction), contact.offsetB.Cross(direction)
+	resistance := contact.a.inverseMass() + contact.b.inverseMass() +
+		crossA*crossA*contact.inertiaA + crossB*crossB*contact.inertiaB
+	if resistance == 0 {
+		return 0
+	}
+
+	return 1 / resistance
+}
+
+// bodyFriction returns the friction of a body, using the default friction
+// for bodies that are not rigid bodies.
+func bodyFriction(body *physicsBody) float64 {
+	if body.RigidBody == nil {
+		return 0.3
+	}
+
+	return body.Friction
+}
+
+// shapeInertia returns the rotational inertia of a shape with the given mass,
+// approximated from its bounds.
+func shapeInertia(shape IShape, mass float64) float64 {
+	if mass <= 0 {
+		mass = 1
+	}
+
+	if circle, isCircle := shape.(Circle); isCircle {
+		return mass * circle.Radius * circle.Radius / 2
+	}
+
+	bounds := shape.GetBounds()
+	return mass * (bounds.Width*bounds.Width + bounds.Height*bounds.Height) / 12
+}
+
+// contactPoint returns the point where two overlapping shapes touch, given
+// the normal of their contact. It is the middle of the part of the edges or
+// corners of each shape facing the other that overlap each other.
+func contactPoint(a IShape, b IShape, normal Vector) Vector {
+	tangent := normal.Perpendicular()
+	minA, maxA, depthA := supportFeature(a, normal, tangent)
+	minB, maxB, depthB := supportFeature(b, normal.Inverted(), tangent)
+
+	along := (math.Max(minA, minB) + math.Min(maxA, maxB)) / 2
+	across := (depthA - depthB) / 2
+
+	return normal.Scaled(across).Add(tangent.Scaled(along))
+}
+
+// supportFeature returns the range along the tangent covered by the vertices
+// of the shape that are furthest in the given direction, along with how far
+// the shape extends in that direction.
+func supportFeature(shape IShape, direction Vector, tangent Vector) (float64, float64, float64) {
+	vertices := shape.GetVertices()
+	_, furthest := projectVertices(vertices, direction)
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range vertices {
+		if v.Dot(direction) >= furthest-0.5 {
+			min, max = math.Min(min, v.Dot(tangent)), math.Max(max, v.Dot(tangent))
+		}
+	}
+
+	return min, max, furthest + shape.GetRadius()
+}
@@ -0,0 +1,130 @@
+package go2d
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+type testRigidBody struct {
+	Entity
+	body *RigidBody
+}
+
+func (this *testRigidBody) GetEntity() *Entity {
+	return &this.Entity
+}
+
+func (this *testRigidBody) GetRigidBody() *RigidBody {
+	return this.body
+}
+
+func (this *testRigidBody) GetCollider() Rect {
+	return this.Bounds
+}
+
+type testWall struct {
+	Entity
+}
+
+func (this *testWall) GetCollider() Rect {
+	return this.Bounds
+}
+
+func (this *testWall) GetBodyType() BodyType {
+	return BodyTypeStatic
+}
+
+// simulatePile drops a pile of overlapping boxes onto a floor and returns
+// where each of them ended up.
+func simulatePile(t *testing.T) []Vector {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	t.Cleanup(engine.Stop)
+	scene := NewScene(engine, "physics")
+	scene.Physics = NewPhysicsWorld(Vector{Y: 500})
+
+	floor := &testWall{}
+	floor.Bounds = NewRect(-100, 200, 400, 20)
+	scene.AddNamedEntity("floor", 0, floor)
+
+	boxes := []*testRigidBody{}
+	for i := 0; i < 12; i++ {
+		box := &testRigidBody{body: NewRigidBody(BodyTypeDynamic, 1)}
+		box.Bounds = NewRect(float64(i%4)*15, float64(i/4)*15, 20, 20)
+		scene.AddNamedEntity(fmt.Sprintf("box%v", i), 0, box)
+		boxes = append(boxes, box)
+	}
+
+	engine.SetScene(&scene)
+	for i := 0; i < 60; i++ {
+		engine.Step()
+	}
+
+	positions := []Vector{}
+	for _, box := range boxes {
+		positions = append(positions, box.Bounds.Vector)
+	}
+
+	return positions
+}
+
+func TestPhysicsIsDeterministic(t *testing.T) {
+	want := simulatePile(t)
+	for run := 0; run < 5; run++ {
+		got := simulatePile(t)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("run %v: box %v ended up at %v, want %v", run, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestDynamicBodiesFallAndRestOnTheFloor(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	t.Cleanup(engine.Stop)
+	scene := NewScene(engine, "physics")
+	scene.Physics = NewPhysicsWorld(Vector{Y: 500})
+
+	floor := &testWall{}
+	floor.Bounds = NewRect(-100, 200, 400, 20)
+	scene.AddNamedEntity("floor", 0, floor)
+
+	boxes := []*testRigidBody{}
+	for i := 0; i < 3; i++ {
+		box := &testRigidBody{body: NewRigidBody(BodyTypeDynamic, 1)}
+		box.Bounds = NewRect(float64(i)*40, float64(i)*20, 20, 20)
+		scene.AddNamedEntity(fmt.Sprintf("box%v", i), 0, box)
+		boxes = append(boxes, box)
+	}
+
+	engine.SetScene(&scene)
+	for i := 0; i < 10; i++ {
+		engine.Step()
+	}
+
+	for i, box := range boxes {
+		if box.Bounds.Y <= float64(i)*20 || box.body.LinearVelocity.Y <= 0 {
+			t.Fatalf("box %v is at %v moving at %v, want it to be falling", i, box.Bounds.Vector, box.body.LinearVelocity)
+		}
+	}
+
+	for i := 0; i < 110; i++ {
+		engine.Step()
+	}
+
+	for i, box := range boxes {
+		if bottom := box.Bounds.Y + box.Bounds.Height; math.Abs(bottom-200) > 1 {
+			t.Errorf("box %v rests with its bottom at %v, want 200", i, bottom)
+		}
+		if box.Bounds.X != float64(i)*40 {
+			t.Errorf("box %v slid to x = %v, want %v", i, box.Bounds.X, float64(i)*40)
+		}
+		if math.Abs(box.body.LinearVelocity.Y) > 10 {
+			t.Errorf("box %v is still moving at %v", i, box.body.LinearVelocity)
+		}
+		if box.body.Inertia != 0 {
+			t.Errorf("box %v has an inertia of %v, want it to be left unset", i, box.body.Inertia)
+		}
+	}
+}
@@ -96,6 +96,15 @@ func (this *Paddle) Update(engine *go2d.Engine) {
 	}
 }
 
+func (this *Paddle) GetCollider() go2d.Rect {
+	return this.Bounds
+}
+
+// Paddles push the ball out instead of letting it clip into them
+func (this *Paddle) GetBodyType() go2d.BodyType {
+	return go2d.BodyTypeKinematic
+}
+
 func (this *Paddle) Constrain(engine *go2d.Engine) []go2d.RectSide {
 	return this.Bounds.Constrain(engine.Bounds())
 }
@@ -107,58 +116,65 @@ type Ball struct {
 
 func (this *Ball) Update(engine *go2d.Engine) {
 	this.Entity.Update()
-	player := engine.GetScene().GetEntity(1, "player").(*Paddle)
-	cpu := engine.GetScene().GetEntity(1, "cpu").(*Paddle)
 
-	// handle paddle collision
-	if this.Entity.CollidesWith(&player.Entity) || this.Entity.CollidesWith(&cpu.Entity) {
-		this.direction = this.direction.InvertedX()
+	// handle scoring
+	if this.Bounds.X < PADDLE_WIDTH {
+		CPU_SCORE += 1
+		SCORE_DISPLAY = fmt.Sprintf("%v   %v", PLAYER_SCORE, CPU_SCORE)
+		PLAYER_PADDLE_MULTIPLIER = 0
+		this.ReSpawn(go2d.GetActiveEngine(), go2d.DirectionLeft())
+	} else if this.Bounds.X > engine.Bounds().Width-PADDLE_WIDTH {
+		PLAYER_SCORE += 1
+		SCORE_DISPLAY = fmt.Sprintf("%v   %v", PLAYER_SCORE, CPU_SCORE)
+		CPU_PADDLE_MULTIPLIER = 0
+		this.ReSpawn(go2d.GetActiveEngine(), go2d.DirectionRight())
+	}
+}
 
-		var collidingEntity *Paddle
+func (this *Ball) GetCollider() go2d.Rect {
+	return this.Bounds
+}
 
-		if this.Entity.CollidesWith(&player.Entity) {
-			collidingEntity = player
-			PLAYER_PADDLE_MULTIPLIER += 0.05
-		} else {
-			collidingEntity = cpu
-			CPU_PADDLE_MULTIPLIER += 0.05
-		}
+// handle paddle collision, only once each time the ball reaches a paddle
+func (this *Ball) OnCollisionEnter(other interface{}) {
+	collidingEntity, isPaddle := other.(*Paddle)
+	if !isPaddle {
+		return
+	}
 
-		yMax := float64(10)
-		yMin := float64(-10)
+	this.direction = this.direction.InvertedX()
 
-		deadzone := float64(10)
-		if this.Bounds.Y > collidingEntity.Bounds.Center().Y-deadzone/2 &&
-			this.Bounds.Y < collidingEntity.Bounds.Center().Y+deadzone/2 {
-			yMax = 0
-			yMin = 0
-		}
+	if collidingEntity.aiControlled {
+		CPU_PADDLE_MULTIPLIER += 0.05
+	} else {
+		PLAYER_PADDLE_MULTIPLIER += 0.05
+	}
 
-		if this.Bounds.IsAbove(collidingEntity.Bounds.Center()) {
-			yMax = 2
-		} else {
-			yMin = -2
-		}
+	yMax := float64(10)
+	yMin := float64(-10)
 
-		this.Velocity = go2d.NewVelocityVector(
-			AI_BALL_RATE*this.direction.X,
-			yMin+rand.Float64()*(yMax-yMin),
-			AI_BALL_TIME,
-		)
+	deadzone := float64(10)
+	if this.Bounds.Y > collidingEntity.Bounds.Center().Y-deadzone/2 &&
+		this.Bounds.Y < collidingEntity.Bounds.Center().Y+deadzone/2 {
+		yMax = 0
+		yMin = 0
+	}
+
+	if this.Bounds.IsAbove(collidingEntity.Bounds.Center()) {
+		yMax = 2
 	} else {
-		// handle scoring
-		if this.Bounds.X < PADDLE_WIDTH {
-			CPU_SCORE += 1
-			SCORE_DISPLAY = fmt.Sprintf("%v   %v", PLAYER_SCORE, CPU_SCORE)
-			PLAYER_PADDLE_MULTIPLIER = 0
-			this.ReSpawn(go2d.GetActiveEngine(), go2d.DirectionLeft())
-		} else if this.Bounds.X > engine.Bounds().Width-PADDLE_WIDTH {
-			PLAYER_SCORE += 1
-			SCORE_DISPLAY = fmt.Sprintf("%v   %v", PLAYER_SCORE, CPU_SCORE)
-			CPU_PADDLE_MULTIPLIER = 0
-			this.ReSpawn(go2d.GetActiveEngine(), go2d.DirectionRight())
-		}
+		yMin = -2
 	}
+
+	this.Velocity = go2d.NewVelocityVector(
+		AI_BALL_RATE*this.direction.X,
+		yMin+rand.Float64()*(yMax-yMin),
+		AI_BALL_TIME,
+	)
+}
+
+func (this *Ball) GetBodyType() go2d.BodyType {
+	return go2d.BodyTypeDynamic
 }
 
 func (this *Ball) Constrain(engine *go2d.Engine) []go2d.RectSide {
@@ -247,6 +263,19 @@ func (this *PongScene) Initialize(engine *go2d.Engine, scene *go2d.Scene) {
 	scene.AddNamedEntity("ball", 2, ball)
 }
 
+// NewPongScene creates the pong scene for the given engine. It is kept
+// separate from main so that the game can also be driven by a headless engine.
+func NewPongScene(engine *go2d.Engine) *go2d.Scene {
+	pongScene := &PongScene{}
+
+	scene := go2d.NewScene(engine, "Level 1")
+	scene.Initializer = pongScene
+
+	scene.RenderStats("../test_resources/font.ttf", 24, "#ff0000")
+
+	return &scene
+}
+
 func main() {
 	engine := go2d.NewEngine(
 		"Pong",
@@ -255,14 +284,7 @@ func main() {
 		).NewDimensions(1200),
 	)
 
-	pongScene := &PongScene{}
-
-	scene := go2d.NewScene(engine, "Level 1")
-	scene.Initializer = pongScene
-
-	scene.RenderStats("../test_resources/font.ttf", 24, "#ff0000")
-
-	engine.SetScene(&scene)
+	engine.SetScene(NewPongScene(engine))
 	engine.HideCursor = true
 	engine.Run()
 }
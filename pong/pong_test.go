@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nathan-fiscaletti/go2d"
+)
+
+func newHeadlessPong(t *testing.T) *go2d.Engine {
+	engine := go2d.NewHeadlessEngine(
+		"Pong",
+		go2d.NewAspectRatio(
+			16, 9, go2d.AspectRatioControlAxisWidth,
+		).NewDimensions(1200),
+	)
+	t.Cleanup(engine.Stop)
+
+	engine.SetScene(NewPongScene(engine))
+	return engine
+}
+
+func TestPongBallStaysInBounds(t *testing.T) {
+	engine := newHeadlessPong(t)
+	ball := engine.GetScene().GetEntity(2, "ball").(*Ball)
+
+	start := ball.Bounds.Vector
+	for i := 0; i < 600; i++ {
+		engine.Step()
+
+		if !engine.Bounds().Contains(ball.Bounds.Center()) {
+			t.Fatalf("tick %v: ball left the screen at %v", i, ball.Bounds.Vector)
+		}
+	}
+
+	if ball.Bounds.Vector.Equals(start) {
+		t.Errorf("ball did not move from %v", start)
+	}
+}
+
+func TestPongCPUPaddleFollowsBall(t *testing.T) {
+	engine := newHeadlessPong(t)
+	scene := engine.GetScene()
+	ball := scene.GetEntity(2, "ball").(*Ball)
+	cpu := scene.GetEntity(1, "cpu").(*Paddle)
+
+	ball.MoveTo(go2d.Vector{X: engine.Bounds().Center().X, Y: engine.Bounds().Height - BALL_SIZE})
+	ball.Velocity = go2d.NewVelocityVector(0, 0, AI_BALL_TIME)
+
+	start := cpu.Bounds.Y
+	for i := 0; i < 30; i++ {
+		engine.Step()
+	}
+
+	if cpu.Bounds.Y <= start {
+		t.Errorf("cpu paddle did not move towards the ball, y = %v, started at %v", cpu.Bounds.Y, start)
+	}
+}
+
+func TestPongPlayerPaddleMovesWithKeys(t *testing.T) {
+	engine := newHeadlessPong(t)
+	player := engine.GetScene().GetEntity(1, "player").(*Paddle)
+
+	player.KeyDown(0, 0, "ArrowDown")
+	for i := 0; i < 10; i++ {
+		engine.Step()
+	}
+	player.KeyUp(0, 0, "ArrowDown")
+
+	want := 10 * PLAYER_PADDLE_RATE
+	if player.Bounds.Y != want {
+		t.Errorf("player paddle is at y = %v, want %v", player.Bounds.Y, want)
+	}
+}
+
+func TestPongRendersFrame(t *testing.T) {
+	engine := newHeadlessPong(t)
+	engine.Step()
+	engine.RenderFrame()
+
+	frame := engine.GetFrame()
+	ball := engine.GetScene().GetEntity(2, "ball").(*Ball).Bounds.Center()
+	if r, g, b, _ := frame.At(int(ball.X), int(ball.Y)).RGBA(); r == 0 || g == 0 || b == 0 {
+		t.Errorf("ball was not drawn at %v", ball)
+	}
+
+	if _, _, _, a := frame.At(0, 0).RGBA(); a == 0 {
+		t.Errorf("frame was not cleared")
+	}
+}
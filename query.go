@@ -0,0 +1,176 @@
+package go2d
+
+import (
+	"math"
+	"sort"
+)
+
+// RaycastHit describes where a ray hit a collider.
+type RaycastHit struct {
+	// Entity is the entity whose collider was hit.
+	Entity interface{}
+	// Point is the point where the ray hit the collider.
+	Point Vector
+	// Normal is the unit normal of the surface of the collider that was hit.
+	Normal Vector
+	// Distance is the distance from the origin of the ray to the point.
+	Distance float64
+}
+
+// QueryHit describes a collider that overlaps the shape of a query.
+type QueryHit struct {
+	// Entity is the entity whose collider overlaps the shape.
+	Entity interface{}
+	// Contact describes how the shape overlaps the collider. The normal
+	// points from the shape towards the collider.
+	Contact ShapeContact
+}
+
+// Raycast returns the first collider in one of the given collision layers
+// that a ray starting at origin and going in the given direction hits within
+// maxDistance. If the origin is inside of a collider, that collider is hit at
+// a distance of zero.
+//
+// Like all queries, Raycast tests the colliders where they are when it is
+// called, so colliders that were added, removed or moved since the last
+// collision pass are taken into account.
+func (this *Scene) Raycast(origin Vector, direction Vector, maxDistance float64, mask CollisionLayer) (RaycastHit, bool) {
+	direction = direction.Normalized()
+	if direction.IsZero() {
+		return RaycastHit{}, false
+	}
+
+	end := origin.Add(direction.Scaled(maxDistance))
+	bounds := unionRects(NewRect(origin.X, origin.Y, 0, 0), NewRect(end.X, end.Y, 0, 0))
+
+	closest := RaycastHit{Distance: math.Inf(1)}
+	point := NewCircle(origin, 0)
+	this.queryColliders(bounds, mask, func(c *collider) {
+		if ShapesIntersect(point, c.shape) {
+			if closest.Distance > 0 {
+				closest = RaycastHit{Entity: c.entity, Point: origin, Normal: direction.Inverted()}
+			}
+			return
+		}
+
+		hit, hits := castRounded(origin, end.Sub(origin), convexHull(c.shape.GetVertices()), c.shape.GetRadius())
+		if hits && hit.Time*maxDistance < closest.Distance {
+			closest = RaycastHit{
+				Entity:   c.entity,
+				Point:    origin.Add(direction.Scaled(hit.Time * maxDistance)),
+				Normal:   hit.Normal,
+				Distance: hit.Time * maxDistance,
+			}
+		}
+	})
+
+	return closest, closest.Entity != nil
+}
+
+// QueryPoint returns every entity with a collider in one of the given
+// collision layers that contains the given point.
+func (this *Scene) QueryPoint(point Vector, mask CollisionLayer) []interface{} {
+	entities := []interface{}{}
+	for _, hit := range this.QueryShape(NewCircle(point, 0), mask) {
+		entities = append(entities, hit.Entity)
+	}
+
+	return entities
+}
+
+// QueryRect returns every collider in one of the given collision layers that
+// overlaps the given rectangle.
+func (this *Scene) QueryRect(r Rect, mask CollisionLayer) []QueryHit {
+	return this.QueryShape(r, mask)
+}
+
+// QueryCircle returns every collider in one of the given collision layers
+// that overlaps the circle with the given center and radius.
+func (this *Scene) QueryCircle(center Vector, radius float64, mask CollisionLayer) []QueryHit {
+	return this.QueryShape(NewCircle(center, radius), mask)
+}
+
+// QueryShape returns every collider in one of the given collision layers that
+// overlaps the given shape.
+func (this *Scene) QueryShape(shape IShape, mask CollisionLayer) []QueryHit {
+	hits := []QueryHit{}
+	this.queryColliders(shape.GetBounds(), mask, func(c *collider) {
+		if contact, intersects := CollideShapes(shape, c.shape); intersects {
+			hits = append(hits, QueryHit{Entity: c.entity, Contact: contact})
+		}
+	})
+
+	return hits
+}
+
+// queryColliders calls cb with the current shape of every collider in one of
+// the given collision layers that may overlap the given bounds, in the order
+// that they were added. The broad phase of the scene is used to find them
+// when the scene has one, except during the collision pass, which is using
+// it to find the colliders near each other.
+func (this *Scene) queryColliders(bounds Rect, mask CollisionLayer, cb func(c *collider)) {
+	if this.BroadPhase == nil || this.colliding {
+		for _, c := range this.collectColliders() {
+			c.movement = Vector{}
+			c.refresh()
+			if c.category&mask != 0 && rectsTouch(c.bounds, bounds) {
+				cb(c)
+			}
+		}
+		return
+	}
+
+	this.updateQueryable()
+
+	found := []*collider{}
+	this.BroadPhase.Query(bounds, func(item interface{}) {
+		c := item.(*collider)
+		if c.category&mask != 0 && rectsTouch(c.bounds, bounds) {
+			found = append(found, c)
+		}
+	})
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].index < found[j].index
+	})
+	for _, c := range found {
+		cb(c)
+	}
+}
+
+// updateQueryable makes sure that the broad phase of the scene holds the
+// current bounds of every collider in the scene. The colliders are collected
+// again when entities were added or removed since they were last collected,
+// and the broad phase is refilled when any of them moved.
+func (this *Scene) updateQueryable() {
+	changes := entitiesChanged.Load()
+	stale := this.queryable == nil || this.queryableAt != changes || this.queryablePhase != this.BroadPhase
+	if stale {
+		this.queryable, this.queryableAt, this.queryablePhase = this.collectColliders(), changes, this.BroadPhase
+	}
+
+	for _, c := range this.queryable {
+		inserted := c.swept
+		c.movement = Vector{}
+		c.category, c.mask = collisionFilter(c.entity)
+		c.refresh()
+		stale = stale || c.swept != inserted
+	}
+
+	if !stale {
+		return
+	}
+
+	this.BroadPhase.Clear()
+	for _, c := range this.queryable {
+		this.BroadPhase.Insert(c, c.swept)
+	}
+}
+
+// rectsTouch returns true if the rectangles overlap or touch. Unlike
+// IntersectsWith, it finds the rectangles that the zero width bounds of a
+// vertical or horizontal ray pass through.
+func rectsTouch(a Rect, b Rect) bool {
+	return a.X <= b.X+b.Width && b.X <= a.X+a.Width &&
+		a.Y <= b.Y+b.Height && b.Y <= a.Y+a.Height
+}
@@ -0,0 +1,104 @@
+package go2d
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestQueriesSeeChangesSinceTheCollisionPass(t *testing.T) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	scene := NewScene(engine, "queries")
+
+	moved := &solidCollider{name: "moved"}
+	moved.Bounds = NewRect(0, 0, 10, 10)
+	scene.AddNamedEntity(moved.name, 0, moved)
+
+	removed := &solidCollider{name: "removed"}
+	removed.Bounds = NewRect(100, 0, 10, 10)
+	scene.AddNamedEntity(removed.name, 0, removed)
+
+	scene.performCollisions(engine)
+
+	added := &solidCollider{name: "added"}
+	added.Bounds = NewRect(200, 0, 10, 10)
+	scene.AddNamedEntity(added.name, 0, added)
+	moved.Bounds.Y = 300
+	scene.RemoveEntity(0, removed.name)
+
+	queries := []struct {
+		name string
+		hits []interface{}
+		want interface{}
+	}{
+		{"added", scene.QueryPoint(Vector{X: 205, Y: 5}, CollisionLayerAll), added},
+		{"moved", scene.QueryPoint(Vector{X: 5, Y: 305}, CollisionLayerAll), moved},
+		{"removed", scene.QueryPoint(Vector{X: 105, Y: 5}, CollisionLayerAll), nil},
+		{"old position", scene.QueryPoint(Vector{X: 5, Y: 5}, CollisionLayerAll), nil},
+	}
+	for _, query := range queries {
+		if query.want == nil && len(query.hits) != 0 || query.want != nil && (len(query.hits) != 1 || query.hits[0] != query.want) {
+			t.Errorf("%v: query found %v", query.name, query.hits)
+		}
+	}
+
+	hit, hits := scene.Raycast(Vector{X: 5, Y: 0}, DirectionDown(), 1000, CollisionLayerAll)
+	if !hits || hit.Entity != moved || hit.Distance != 300 {
+		t.Errorf("vertical raycast hit %v, want the moved collider at a distance of 300", hit)
+	}
+}
+
+func TestQueriesFindCollidersThatMovedSinceTheLastQuery(t *testing.T) {
+	broadPhases := []struct {
+		name       string
+		broadPhase IBroadPhase
+	}{
+		{"spatial hash", NewSpatialHash(DefaultCollisionCellSize)},
+		{"quad tree", NewQuadTree(NewRect(0, 0, 1024, 1024), 2, 4)},
+		{"no broad phase", nil},
+	}
+
+	for _, test := range broadPhases {
+		t.Run(test.name, func(t *testing.T) {
+			engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+			scene := NewScene(engine, "queries")
+			scene.BroadPhase = test.broadPhase
+
+			colliders := []*solidCollider{}
+			for i := 0; i < 8; i++ {
+				c := &solidCollider{name: fmt.Sprintf("c%v", i)}
+				c.Bounds = NewRect(float64(i)*100, 0, 10, 10)
+				scene.AddNamedEntity(c.name, 0, c)
+				colliders = append(colliders, c)
+			}
+
+			scene.performCollisions(engine)
+
+			moved := colliders[3]
+			for _, position := range []Vector{{X: 600, Y: 500}, {X: 5, Y: 900}, {X: 300, Y: 0}} {
+				old := moved.Bounds.Vector
+				moved.Bounds.Vector = position
+
+				hits := scene.QueryPoint(position.Add(Vector{X: 5, Y: 5}), CollisionLayerAll)
+				if len(hits) != 1 || hits[0] != moved {
+					t.Errorf("query at %v found %v, want the moved collider", position, hits)
+				}
+
+				if old != position {
+					if hits := scene.QueryPoint(old.Add(Vector{X: 5, Y: 5}), CollisionLayerAll); len(hits) != 0 {
+						t.Errorf("query at the old position %v found %v", old, hits)
+					}
+				}
+			}
+
+			hits := scene.QueryRect(NewRect(0, 0, 1000, 10), CollisionLayerAll)
+			if len(hits) != len(colliders) {
+				t.Fatalf("query found %v colliders, want %v", len(hits), len(colliders))
+			}
+			for i, hit := range hits {
+				if hit.Entity != colliders[i] {
+					t.Errorf("hit %v is %v, want %v", i, hit.Entity, colliders[i].name)
+				}
+			}
+		})
+	}
+}
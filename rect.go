@@ -1,5 +1,9 @@
 package go2d
 
+import (
+	"math"
+)
+
 // RectSide is an enumeration of the sides of a rectangle.
 type RectSide int
 
@@ -68,6 +72,33 @@ func (this Rect) Center() Vector {
 	}
 }
 
+// MinimumTranslation returns the shortest vector that moves this rectangle
+// out of the given rectangle, along with the side of this rectangle that was
+// overlapping it. If the rectangles do not intersect, false is returned.
+func (this Rect) MinimumTranslation(other Rect) (Vector, RectSide, bool) {
+	if !this.IntersectsWith(other) {
+		return Vector{}, RectSideLeft, false
+	}
+
+	left := this.X + this.Width - other.X
+	right := other.X + other.Width - this.X
+	up := this.Y + this.Height - other.Y
+	down := other.Y + other.Height - this.Y
+
+	mtv, side := Vector{X: -left}, RectSide(RectSideRight)
+	if right < math.Abs(mtv.X) {
+		mtv, side = Vector{X: right}, RectSideLeft
+	}
+	if up < mtv.Length() {
+		mtv, side = Vector{Y: -up}, RectSideBottom
+	}
+	if down < mtv.Length() {
+		mtv, side = Vector{Y: down}, RectSideTop
+	}
+
+	return mtv, side, true
+}
+
 // Constrain constrains this rectangle to the given rectangle. If this rectangle
 // is outside of the given rectangle, it will be moved to the closest point on
 // the rectangle. The return value is a slice of the sides that were constrained.
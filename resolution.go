@@ -0,0 +1,185 @@
+package go2d
+
+import (
+	"math"
+)
+
+// resolutionSlop is how far solid bodies are left overlapping after they are
+// pushed apart, so that bodies resting against each other keep colliding
+// instead of separating and colliding again every tick.
+const resolutionSlop = 0.01
+
+// BodyType controls how a solid entity is moved when it overlaps other solid
+// entities.
+type BodyType int
+
+const (
+	// BodyTypeNone is used by entities that are not solid. They detect
+	// collisions, but never push or get pushed.
+	BodyTypeNone BodyType = iota
+	// BodyTypeStatic is used by solid entities that never move, such as walls.
+	BodyTypeStatic
+	// BodyTypeKinematic is used by solid entities that move on their own,
+	// such as moving platforms. They push dynamic bodies, but are never pushed
+	// themselves.
+	BodyTypeKinematic
+	// BodyTypeDynamic is used by solid entities that are pushed out of any
+	// other solid entity they overlap.
+	BodyTypeDynamic
+)
+
+// IEntityBody is an interface that can be implemented by entities that
+// implement IEntityCollider or IEntityShapeCollider to make them solid. After
+// the collision pass, dynamic bodies are pushed out of the solid bodies they
+// overlap along the minimum translation vector, and the part of their velocity
+// moving into the other body is removed.
+type IEntityBody interface {
+	GetBodyType() BodyType
+}
+
+// IEntityCollisionResolvedHandler is an interface that can be implemented by
+// dynamic bodies that want to be notified when they are pushed out of another
+// solid entity. The side is the side of the entity that was touching the
+// other entity, like the sides reported by Rect.Constrain.
+type IEntityCollisionResolvedHandler interface {
+	OnCollisionResolved(other interface{}, side RectSide)
+}
+
+// SideOfNormal returns the side of a rectangle that faces in the direction of
+// the given normal.
+func SideOfNormal(normal Vector) RectSide {
+	if math.Abs(normal.X) >= math.Abs(normal.Y) {
+		if normal.X > 0 {
+			return RectSideRight
+		}
+		return RectSideLeft
+	}
+
+	if normal.Y > 0 {
+		return RectSideBottom
+	}
+	return RectSideTop
+}
+
+// resolveCollisions pushes every dynamic body out of the solid bodies it
+// overlaps.
+func (this *Scene) resolveCollisions(colliders []*collider, nearby func(c *collider, cb func(other *collider))) {
+	for _, c := range colliders {
+		if c.body != BodyTypeDynamic {
+			continue
+		}
+
+		nearby(c, func(other *collider) {
+			if other == c || other.body == BodyTypeNone {
+				return
+			}
+
+			// Pairs of dynamic bodies are only resolved once.
+			if other.body == BodyTypeDynamic && other.index < c.index {
+				return
+			}
+
+			if !c.interactsWith(other, this.CollisionMatrix) {
+				return
+			}
+
+			if !this.isInScene(c) || !this.isInScene(other) {
+				return
+			}
+
+			this.resolve(c, other)
+		})
+	}
+}
+
+// resolve pushes the dynamic body c out of the solid body other.
+func (this *Scene) resolve(c *collider, other *collider) {
+	entity, isEntity := c.entity.(IEntity)
+	if !isEntity {
+		return
+	}
+
+	// Either body may have already been moved by another pair this tick.
+	c.refresh()
+	other.refresh()
+
+	var contact ShapeContact
+	var intersects bool
+	a, isRect := c.shape.(Rect)
+	b, isOtherRect := other.shape.(Rect)
+	if isRect && isOtherRect {
+		var mtv Vector
+		mtv, _, intersects = a.MinimumTranslation(b)
+		contact = ShapeContact{Normal: mtv.Inverted().Normalized(), Depth: mtv.Length()}
+	} else {
+		contact, intersects = CollideShapes(c.shape, other.shape)
+	}
+
+	if !intersects {
+		this.resolveSweep(c, other)
+		return
+	}
+
+	depth := contact.Depth - resolutionSlop
+	if depth <= 0 {
+		return
+	}
+
+	otherEntity, isOtherEntity := other.entity.(IEntity)
+	if other.body == BodyTypeDynamic && isOtherEntity {
+		depth /= 2
+		pushOut(otherEntity.GetEntity(), contact.Normal, depth)
+		other.refresh()
+		if handler, isHandler := other.entity.(IEntityCollisionResolvedHandler); isHandler {
+			handler.OnCollisionResolved(c.entity, SideOfNormal(contact.Normal.Inverted()))
+		}
+	}
+
+	pushOut(entity.GetEntity(), contact.Normal.Inverted(), depth)
+	c.refresh()
+	if handler, isHandler := c.entity.(IEntityCollisionResolvedHandler); isHandler {
+		handler.OnCollisionResolved(other.entity, SideOfNormal(contact.Normal))
+	}
+}
+
+// resolveSweep moves a bullet back to where it first touched the solid body
+// other during the tick, if it passed through it.
+func (this *Scene) resolveSweep(c *collider, other *collider) {
+	if c.movement.IsZero() {
+		return
+	}
+
+	hit, hits := c.sweep(other)
+	if !hits {
+		return
+	}
+
+	entity := c.entity.(IEntity).GetEntity()
+	pushWorld(entity, c.movement.Scaled(hit.Time-1))
+	pushOut(entity, hit.Normal, 0)
+	c.movement = c.movement.Scaled(hit.Time)
+	c.refresh()
+
+	if handler, isHandler := c.entity.(IEntityCollisionResolvedHandler); isHandler {
+		handler.OnCollisionResolved(other.entity, SideOfNormal(hit.Normal.Inverted()))
+	}
+}
+
+// pushOut moves the entity by depth in the direction of the normal and
+// removes the part of its velocity moving against the normal. The normal is
+// in the space of the scene.
+func pushOut(e *Entity, normal Vector, depth float64) {
+	pushWorld(e, normal.Scaled(depth))
+
+	toScene := e.parent.sceneTransform()
+	velocity := toScene.ApplyVector(e.Velocity.Vector)
+	into := velocity.Dot(normal)
+	if into < 0 {
+		e.Velocity.Vector = toScene.Inverse().ApplyVector(velocity.Sub(normal.Scaled(into)))
+	}
+}
+
+// pushWorld moves the entity by an offset in the space of the scene.
+func pushWorld(e *Entity, offset Vector) {
+	e.Push(e.parent.sceneTransform().Inverse().ApplyVector(offset))
+}
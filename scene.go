@@ -2,6 +2,7 @@ package go2d
 
 import (
 	"fmt"
+	"sort"
 )
 
 type ISceneInitializer interface {
@@ -20,6 +21,26 @@ type ISceneUpdater interface {
 	Update(engine *Engine, scene *Scene)
 }
 
+type ISceneEnterHandler interface {
+	OnEnter(engine *Engine, scene *Scene)
+}
+
+type IScenePauseHandler interface {
+	OnPause(engine *Engine, scene *Scene)
+}
+
+type ISceneResumeHandler interface {
+	OnResume(engine *Engine, scene *Scene)
+}
+
+type ISceneExitHandler interface {
+	OnExit(engine *Engine, scene *Scene)
+}
+
+type ISceneResizeHandler interface {
+	OnResize(engine *Engine, scene *Scene, size Dimensions)
+}
+
 // Scene is a simple scene implementation.
 type Scene struct {
 	*EntityGroup
@@ -35,27 +56,84 @@ type Scene struct {
 	Renderer ISceneRenderer
 	// Updater is the updater that will be called when the scene is updated.
 	Updater ISceneUpdater
-
-	renderStats bool
-	statsEntity *TextEntity
-	engine      *Engine
-	resources   map[string]interface{}
-	timers      map[string]*timer
+	// EnterHandler is the handler that will be called when the scene is added
+	// to the scene stack of the engine, after it has been initialized.
+	EnterHandler ISceneEnterHandler
+	// PauseHandler is the handler that will be called when another scene is
+	// pushed on top of the scene.
+	PauseHandler IScenePauseHandler
+	// ResumeHandler is the handler that will be called when the scene becomes
+	// the top of the scene stack again because the scene above it was popped.
+	ResumeHandler ISceneResumeHandler
+	// ExitHandler is the handler that will be called when the scene is exited,
+	// either because it was removed from the scene stack or because the
+	// engine stopped.
+	ExitHandler ISceneExitHandler
+	// UpdateScenesBelow keeps the scenes below the scene in the scene stack
+	// updating while the scene is on top of them. Otherwise only the scene
+	// and the scenes above it are updated.
+	UpdateScenesBelow bool
+	// RenderScenesBelow keeps the scenes below the scene in the scene stack
+	// rendering beneath it, which is useful for pause menus and dialogs.
+	// Otherwise only the scene and the scenes above it are rendered.
+	RenderScenesBelow bool
+	// ResizeHandler is the handler that will be called when the window is
+	// resized. It receives the new size of the window in pixels.
+	ResizeHandler ISceneResizeHandler
+	// Camera is the camera that world space layers of the scene are viewed
+	// through.
+	Camera *Camera
+	// BroadPhase is used to find the colliders that are near each other
+	// during the collision pass. If it is nil, every pair of colliders is
+	// tested.
+	BroadPhase IBroadPhase
+	// CollisionMatrix controls which collision layers interact with each
+	// other in this scene. If it is nil, every collision layer interacts with
+	// every other collision layer.
+	CollisionMatrix *CollisionMatrix
+	// Physics is the physics world that simulates the entities of the scene
+	// that implement IEntityRigidBody. If it is nil, they are not simulated.
+	Physics *PhysicsWorld
+	// Tweens are the tweens that are animating values in the scene. They are
+	// advanced at the start of every tick, before the entities are updated.
+	Tweens *TweenManager
+
+	renderStats  bool
+	statsEntity  *TextEntity
+	engine       *Engine
+	resources    map[string]interface{}
+	timers       map[string]*Timer
+	coroutines   []*Coroutine
+	screenLayers map[int]bool
+	contacts     map[contact]*collider
+	colliding    bool
+	// queryable are the colliders in the broad phase, which queries use
+	// until entities are added to or removed from any entity group.
+	queryable      []*collider
+	queryableAt    uint64
+	queryablePhase IBroadPhase
 }
 
 // GetActiveScene returns the active scene. If no scene is active, nil is returned. If you are using
 // multiple scenes, you should use GetActiveScenes() otherwise you will get a panic.
 func GetActiveScene() *Scene {
-	engineCount := len(GetActiveEngines())
-	if engineCount < 1 {
-		return nil
+	scene, err := GetActiveSceneE()
+	if err != nil {
+		panic("More than one running Engine. Please use GetActiveScenes().")
 	}
 
-	if engineCount > 1 {
-		panic("More than one running Engine. Please use GetActiveScenes().")
+	return scene
+}
+
+// GetActiveSceneE returns the active scene. If no scene is active, nil is
+// returned. If more than one engine is running, ErrMultipleEngines is returned.
+func GetActiveSceneE() (*Scene, error) {
+	engine, err := GetActiveEngineE()
+	if engine == nil || err != nil {
+		return nil, err
 	}
 
-	return GetActiveEngine().GetScene()
+	return engine.GetScene(), nil
 }
 
 // GetActiveScenes returns all active scenes.
@@ -71,11 +149,15 @@ func GetActiveScenes() []*Scene {
 // NewScene creates a new scene with the given name.
 func NewScene(engine *Engine, name string) Scene {
 	return Scene{
-		EntityGroup: NewEntityGroup(),
-		engine:      engine,
-		timers:      map[string]*timer{},
-		resources:   map[string]interface{}{},
-		Name:        name,
+		EntityGroup:  newSceneRoot(),
+		Camera:       NewCamera(engine.Dimensions),
+		BroadPhase:   NewSpatialHash(DefaultCollisionCellSize),
+		Tweens:       NewTweenManager(),
+		engine:       engine,
+		timers:       map[string]*Timer{},
+		resources:    map[string]interface{}{},
+		screenLayers: map[int]bool{},
+		Name:         name,
 	}
 }
 
@@ -91,16 +173,83 @@ func (this *Scene) StopRenderingStats() {
 	this.renderStats = false
 }
 
-// AddTimer adds a timer to the scene.
-func (this *Scene) AddTimer(name string, t *timer) {
+// SetScreenSpaceLayer sets whether the entities in the given layer are drawn
+// directly onto the screen instead of being viewed through the camera. This is
+// useful for HUDs and menus.
+func (this *Scene) SetScreenSpaceLayer(layer int, screenSpace bool) {
+	if screenSpace {
+		this.screenLayers[layer] = true
+	} else {
+		delete(this.screenLayers, layer)
+	}
+}
+
+// IsScreenSpaceLayer returns true if the entities in the given layer are drawn
+// directly onto the screen instead of being viewed through the camera.
+func (this *Scene) IsScreenSpaceLayer(layer int) bool {
+	return this.screenLayers[layer]
+}
+
+// ScreenToWorld converts a position on the screen to a position in the world
+// as seen through the camera of the scene.
+func (this *Scene) ScreenToWorld(pos Vector) Vector {
+	return this.Camera.ScreenToWorld(pos)
+}
+
+// WorldToScreen converts a position in the world to a position on the screen
+// as seen through the camera of the scene.
+func (this *Scene) WorldToScreen(pos Vector) Vector {
+	return this.Camera.WorldToScreen(pos)
+}
+
+// AddTimer adds a timer to the scene. A timer that was already added with the
+// same name is replaced.
+func (this *Scene) AddTimer(name string, t *Timer) {
 	this.timers[name] = t
 }
 
+// GetTimer returns the timer with the given name, or nil if the scene does
+// not have a timer with that name.
+func (this *Scene) GetTimer(name string) *Timer {
+	return this.timers[name]
+}
+
 // RemoveTimer removes a timer from the scene.
 func (this *Scene) RemoveTimer(name string) {
 	delete(this.timers, name)
 }
 
+// AddTween starts playing a tween in the scene and returns it.
+func (this *Scene) AddTween(tween *Tween) *Tween {
+	return this.Tweens.Add(tween)
+}
+
+// RemoveTween stops playing a tween in the scene.
+func (this *Scene) RemoveTween(tween *Tween) {
+	this.Tweens.Remove(tween)
+}
+
+// StartCoroutine starts running the given function as a coroutine of the
+// scene and returns the coroutine. It first runs during the next tick.
+func (this *Scene) StartCoroutine(fn func(co *Coroutine)) *Coroutine {
+	return this.AddCoroutine(NewCoroutine(fn))
+}
+
+// AddCoroutine adds a coroutine to the scene and returns it. It first runs
+// during the next tick.
+func (this *Scene) AddCoroutine(co *Coroutine) *Coroutine {
+	this.coroutines = append(this.coroutines, co)
+	return co
+}
+
+// StopCoroutines stops all of the coroutines of the scene. This is done
+// automatically when the scene is exited.
+func (this *Scene) StopCoroutines() {
+	coroutines := this.coroutines
+	this.coroutines = nil
+	stopCoroutines(coroutines)
+}
+
 // GetResource returns a resource by name.
 func (this *Scene) GetResource(name string) interface{} {
 	return this.resources[name]
@@ -118,8 +267,27 @@ func (this *Scene) ClearResources() {
 	}
 }
 
+// iterateEntitiesAt iterates over all entities in the scene along with the
+// given screen position converted into the space of the entity's layer.
+func (this *Scene) iterateEntitiesAt(pos Vector, cb func(e interface{}, pos Vector)) {
+	worldPos := this.ScreenToWorld(pos)
+	for _, layer := range this.layers() {
+		layerPos := worldPos
+		if this.IsScreenSpaceLayer(layer) {
+			layerPos = pos
+		}
+
+		this.iterateLayer(layer, func(e interface{}) {
+			cb(e, layerPos)
+			if group, isGroup := e.(entityGroup); isGroup {
+				group.group().walkEntitiesAt(layerPos, cb)
+			}
+		})
+	}
+}
+
 func (this *Scene) notifyMouseMove(pos Vector) {
-	this.IterateEntities(func(e interface{}) {
+	this.iterateEntitiesAt(pos, func(e interface{}, pos Vector) {
 		_, isMouseSensitive := e.(IMouseMove)
 		if isMouseSensitive {
 			e.(IMouseMove).MouseMove(pos)
@@ -128,7 +296,7 @@ func (this *Scene) notifyMouseMove(pos Vector) {
 }
 
 func (this *Scene) notifyMouseUp(button int, pos Vector) {
-	this.IterateEntities(func(e interface{}) {
+	this.iterateEntitiesAt(pos, func(e interface{}, pos Vector) {
 		_, isMouseSensitive := e.(IMouseUp)
 		if isMouseSensitive {
 			e.(IMouseUp).MouseUp(button, pos)
@@ -137,7 +305,7 @@ func (this *Scene) notifyMouseUp(button int, pos Vector) {
 }
 
 func (this *Scene) notifyMouseDown(button int, pos Vector) {
-	this.IterateEntities(func(e interface{}) {
+	this.iterateEntitiesAt(pos, func(e interface{}, pos Vector) {
 		_, isMouseSensitive := e.(IMouseDown)
 		if isMouseSensitive {
 			e.(IMouseDown).MouseDown(button, pos)
@@ -146,7 +314,7 @@ func (this *Scene) notifyMouseDown(button int, pos Vector) {
 }
 
 func (this *Scene) notifyKeyUp(scanCode int, rn rune, name string) {
-	this.IterateEntities(func(e interface{}) {
+	this.walkEntities(func(e interface{}) {
 		_, isKeySensitive := e.(IKeyUp)
 		if isKeySensitive {
 			e.(IKeyUp).KeyUp(scanCode, rn, name)
@@ -155,7 +323,7 @@ func (this *Scene) notifyKeyUp(scanCode int, rn rune, name string) {
 }
 
 func (this *Scene) notifyKeyDown(scanCode int, rn rune, name string) {
-	this.IterateEntities(func(e interface{}) {
+	this.walkEntities(func(e interface{}) {
 		_, isKeySensitive := e.(IKeyDown)
 		if isKeySensitive {
 			e.(IKeyDown).KeyDown(scanCode, rn, name)
@@ -164,7 +332,7 @@ func (this *Scene) notifyKeyDown(scanCode int, rn rune, name string) {
 }
 
 func (this *Scene) notifyKeyChar(rn rune) {
-	this.IterateEntities(func(e interface{}) {
+	this.walkEntities(func(e interface{}) {
 		_, isKeySensitive := e.(IKeyChar)
 		if isKeySensitive {
 			e.(IKeyChar).KeyChar(rn)
@@ -172,67 +340,143 @@ func (this *Scene) notifyKeyChar(rn rune) {
 	})
 }
 
-func (this *Scene) performUpdate(engine *Engine) {
-	for _, t := range this.timers {
-		t.notifyUpdate(this, this)
+// updateTimers advances the timers of the scene by one tick in the order of
+// their names, and removes the timers that have stopped.
+func (this *Scene) updateTimers(engine *Engine) {
+	names := make([]string, 0, len(this.timers))
+	for name := range this.timers {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	this.EntityGroup.Update(engine)
+	for _, name := range names {
+		t := this.timers[name]
+		if t == nil {
+			continue
+		}
 
-	// Handle constraint and Update calls
-	this.IterateEntities(func(e interface{}) {
-		_, isConstrain := e.(IEntityConstraint)
-		if isConstrain {
-			constrainedSides := e.(IEntityConstraint).Constrain(engine)
-			_, isConstrained := e.(IEntityConstrainedHandler)
-			if isConstrained {
-				for _, side := range constrainedSides {
-					e.(IEntityConstrainedHandler).OnConstrained(side)
-				}
-			}
+		t.advance(this, engine.GetTickDuration())
+		if t.IsStopped() && this.timers[name] == t {
+			delete(this.timers, name)
 		}
+	}
+}
+
+// updateCoroutines resumes the coroutines of the scene in the order they were
+// started, and removes the coroutines that have finished. Coroutines started
+// during the tick first run during the next tick.
+func (this *Scene) updateCoroutines(engine *Engine) {
+	for _, co := range append([]*Coroutine{}, this.coroutines...) {
+		co.step(engine.GetTickDuration())
+	}
 
-		_, isUpdatable := e.(IEntityUpdater)
-		if isUpdatable {
-			e.(IEntityUpdater).Update(engine)
+	running := this.coroutines[:0]
+	for _, co := range this.coroutines {
+		if !co.IsDone() {
+			running = append(running, co)
 		}
-	})
+	}
+	this.coroutines = running
+}
 
-	// Handle Collision
-	this.IterateEntities(func(e interface{}) {
-		_, hasCollisionDetection := e.(IEntityCollisionDetection)
-		_, hasCollider := e.(IEntityCollider)
-		if hasCollisionDetection && hasCollider {
-			this.IterateEntities(func(e2 interface{}) {
-				if e2 != e {
-					_, hasCollider2 := e2.(IEntityCollider)
-					if hasCollider2 {
-						if e.(IEntityCollider).GetCollider().IntersectsWith(
-							e2.(IEntityCollider).GetCollider(),
-						) {
-							e.(IEntityCollisionDetection).CollidedWith(e2)
-						}
-					}
-				}
-			})
+func (this *Scene) performUpdate(engine *Engine) {
+	// Remember where each entity was at the start of the tick so that it can
+	// be interpolated while rendering.
+	this.walkEntities(func(e interface{}) {
+		if entity, isEntity := entityOf(e); isEntity {
+			entity.snapshot(engine.ticks)
 		}
 	})
 
+	this.updateTimers(engine)
+
+	if this.Tweens != nil {
+		this.Tweens.Advance(engine.GetTickDuration())
+	}
+
+	this.updateCoroutines(engine)
+
+	this.tickDuration = engine.GetTickDuration()
+	this.EntityGroup.Push(this.Velocity.GetMovement(this.tickDuration))
+
+	if this.Physics != nil {
+		this.Physics.integrate(this, engine.GetTickDuration())
+	}
+
+	// Handle constraint and Update calls
+	this.updateChildren(engine)
+
+	// Handle Collision
+	this.performCollisions(engine)
+
 	if this.Updater != nil {
 		this.Updater.Update(engine, this)
 	}
 
+	this.Camera.update(engine.Dimensions)
+
 	if this.renderStats {
 		this.statsEntity.SetText(fmt.Sprintf("FPS: %v, TPS: %v", engine.GetFPS(), engine.GetTPS()))
 	}
 }
 
-func (this *Scene) performRender(engine *Engine) {
+func (this *Scene) notifyResize(engine *Engine, size Dimensions) {
+	if this.ResizeHandler != nil {
+		this.ResizeHandler.OnResize(engine, this, size)
+	}
+}
+
+func (this *Scene) performEnter(engine *Engine) {
+	if this.EnterHandler != nil {
+		this.EnterHandler.OnEnter(engine, this)
+	}
+}
+
+func (this *Scene) performPause(engine *Engine) {
+	if this.PauseHandler != nil {
+		this.PauseHandler.OnPause(engine, this)
+	}
+}
+
+func (this *Scene) performResume(engine *Engine) {
+	if this.ResumeHandler != nil {
+		this.ResumeHandler.OnResume(engine, this)
+	}
+}
+
+func (this *Scene) performExit(engine *Engine) {
+	if this.ExitHandler != nil {
+		this.ExitHandler.OnExit(engine, this)
+	}
+
+	this.StopCoroutines()
+}
+
+// performRender renders the scene with the opacity of its entities multiplied
+// by the given alpha, which transitions use to fade scenes in and out.
+func (this *Scene) performRender(engine *Engine, alpha float64) {
+	this.renderFade = 1 - alpha
+	defer func() {
+		this.renderFade = 0
+	}()
+
 	if this.PreRenderer != nil {
 		this.PreRenderer.PreRender(engine, this)
 	}
 
-	this.EntityGroup.Render(engine)
+	offset := this.RenderBounds(engine).Vector
+
+	engine.saveCanvas()
+	engine.transformCanvas(NewTranslateTransform(offset))
+	for _, layer := range this.layers() {
+		engine.saveCanvas()
+		if !this.IsScreenSpaceLayer(layer) {
+			this.Camera.apply(engine)
+		}
+		this.renderLayer(engine, layer)
+		engine.restoreCanvas()
+	}
+	engine.restoreCanvas()
 
 	if this.Renderer != nil {
 		this.Renderer.Render(engine, this)
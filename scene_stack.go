@@ -0,0 +1,275 @@
+package go2d
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// sceneTransition is a transition between two sets of visible scenes that is
+// in progress.
+type sceneTransition struct {
+	transition ISceneTransition
+	from       []*Scene
+	exiting    []*Scene
+	elapsed    time.Duration
+}
+
+// PushScene pushes the given scene on top of the scene stack, pausing the
+// scene that was on top until the new scene is popped. If transition is not
+// nil, it is used to transition to the new scene. The change is deferred in
+// the same way as SetScene.
+func (this *Engine) PushScene(scene *Scene, transition ISceneTransition) {
+	this.changeScenes(func() {
+		this.transitionScenes(transition, func() {
+			if top := this.GetScene(); top != nil {
+				top.performPause(this)
+			}
+			this.enterScene(scene)
+		})
+	})
+}
+
+// PopScene removes the scene on top of the scene stack and resumes the scene
+// below it. If transition is not nil, it is used to transition from the
+// popped scene, which is exited once the transition has completed. The
+// change is deferred in the same way as SetScene.
+func (this *Engine) PopScene(transition ISceneTransition) {
+	this.changeScenes(func() {
+		if len(this.scenes) == 0 {
+			return
+		}
+
+		this.transitionScenes(transition, func() {
+			this.exitScene(this.popScene())
+			if top := this.GetScene(); top != nil {
+				top.performResume(this)
+			}
+		})
+	})
+}
+
+// ReplaceScene replaces the scene on top of the scene stack with the given
+// scene, leaving the scenes below it untouched. If transition is not nil, it
+// is used to transition to the new scene. The change is deferred in the same
+// way as SetScene.
+func (this *Engine) ReplaceScene(scene *Scene, transition ISceneTransition) {
+	this.changeScenes(func() {
+		this.transitionScenes(transition, func() {
+			if len(this.scenes) > 0 {
+				this.exitScene(this.popScene())
+			}
+			this.enterScene(scene)
+		})
+	})
+}
+
+// GetScenes returns the scenes in the scene stack, from the bottom to the top.
+func (this *Engine) GetScenes() []*Scene {
+	this.scenesMux.RLock()
+	defer this.scenesMux.RUnlock()
+
+	return append([]*Scene{}, this.scenes...)
+}
+
+// IsTransitioning returns true if a transition between scenes is in progress.
+func (this *Engine) IsTransitioning() bool {
+	this.scenesMux.RLock()
+	defer this.scenesMux.RUnlock()
+
+	return this.transition != nil
+}
+
+// changeScenes applies a change to the scene stack, or defers it until the
+// engine is unlocked if it is currently locked.
+func (this *Engine) changeScenes(change func()) {
+	this.pendingSceneMux.Lock()
+	if this.locked {
+		this.pendingSceneChanges = append(this.pendingSceneChanges, change)
+		this.pendingSceneMux.Unlock()
+		return
+	}
+	this.pendingSceneMux.Unlock()
+
+	this.lock()
+	change()
+	this.unlock()
+}
+
+// nextSceneChange removes and returns the oldest deferred change to the scene
+// stack. If there is none, the engine is marked as unlocked in the same step
+// and nil is returned, so that changes made from then on are applied by
+// whoever makes them rather than waiting for an unlock that has already
+// happened.
+func (this *Engine) nextSceneChange() func() {
+	this.pendingSceneMux.Lock()
+	defer this.pendingSceneMux.Unlock()
+
+	if len(this.pendingSceneChanges) == 0 {
+		this.locked = false
+		return nil
+	}
+
+	change := this.pendingSceneChanges[0]
+	this.pendingSceneChanges = this.pendingSceneChanges[1:]
+	return change
+}
+
+// transitionScenes applies a change to the scene stack using the given
+// transition. Any transition that is still in progress is completed first.
+// Scenes that are exited during the change are exited once the transition has
+// completed, so that they can still be rendered during it. The engine must be
+// locked.
+func (this *Engine) transitionScenes(transition ISceneTransition, change func()) {
+	this.finishTransition()
+
+	if transition == nil || transition.GetDuration() <= 0 {
+		change()
+		this.updateTitle()
+		return
+	}
+
+	this.setTransition(&sceneTransition{
+		transition: transition,
+		from:       this.visibleScenes(),
+	})
+	change()
+	this.updateTitle()
+}
+
+// advanceTransition moves the transition in progress forward by one tick and
+// completes it once its duration has passed. The engine must be locked.
+func (this *Engine) advanceTransition() {
+	if this.transition == nil {
+		return
+	}
+
+	this.transition.elapsed += this.GetTickDuration()
+	if this.transition.elapsed >= this.transition.transition.GetDuration() {
+		this.finishTransition()
+	}
+}
+
+// finishTransition completes the transition in progress, if any, and exits
+// the scenes that were removed during it. The engine must be locked.
+func (this *Engine) finishTransition() {
+	if this.transition == nil {
+		return
+	}
+
+	exiting := this.transition.exiting
+	this.setTransition(nil)
+	for _, scene := range exiting {
+		this.exitScene(scene)
+	}
+}
+
+// setTransition sets the transition in progress. The engine must be locked.
+func (this *Engine) setTransition(transition *sceneTransition) {
+	this.scenesMux.Lock()
+	this.transition = transition
+	this.scenesMux.Unlock()
+}
+
+// enterScene pushes the given scene on top of the scene stack and initializes
+// it. The engine must be locked.
+func (this *Engine) enterScene(scene *Scene) {
+	this.scenesMux.Lock()
+	this.scenes = append(this.scenes, scene)
+	this.scenesMux.Unlock()
+
+	if scene.Initializer != nil {
+		scene.Initializer.Initialize(this, scene)
+	}
+	scene.performEnter(this)
+}
+
+// popScene removes the scene on top of the scene stack and returns it. The
+// engine must be locked.
+func (this *Engine) popScene() *Scene {
+	this.scenesMux.Lock()
+	defer this.scenesMux.Unlock()
+
+	scene := this.scenes[len(this.scenes)-1]
+	this.scenes = this.scenes[:len(this.scenes)-1]
+	return scene
+}
+
+// exitScene exits a scene that was removed from the scene stack, or defers it
+// until the transition in progress has completed. The engine must be locked.
+func (this *Engine) exitScene(scene *Scene) {
+	if this.transition != nil {
+		this.transition.exiting = append(this.transition.exiting, scene)
+		return
+	}
+
+	scene.performExit(this)
+	scene.ClearResources()
+}
+
+// updateTitle updates the title of the window to the name of the current
+// scene.
+func (this *Engine) updateTitle() {
+	if scene := this.GetScene(); this.window != nil && scene != nil {
+		this.window.setTitle(fmt.Sprintf("%s - %s", this.Name, scene.Name))
+	}
+}
+
+// updatingScenes returns the scenes in the scene stack that should be updated,
+// from the bottom to the top.
+func (this *Engine) updatingScenes() []*Scene {
+	start := len(this.scenes) - 1
+	for start > 0 && this.scenes[start].UpdateScenesBelow {
+		start--
+	}
+
+	return this.scenes[maxInt(start, 0):]
+}
+
+// visibleScenes returns the scenes in the scene stack that should be
+// rendered, from the bottom to the top.
+func (this *Engine) visibleScenes() []*Scene {
+	start := len(this.scenes) - 1
+	for start > 0 && this.scenes[start].RenderScenesBelow {
+		start--
+	}
+
+	return append([]*Scene{}, this.scenes[maxInt(start, 0):]...)
+}
+
+// renderScenes renders the visible scenes, or the transition to them if one
+// is in progress. The engine must be locked.
+func (this *Engine) renderScenes() {
+	visible := this.visibleScenes()
+	if this.transition == nil {
+		this.renderSceneList(visible, 1)
+		return
+	}
+
+	// The progress is interpolated between ticks, like the entities are.
+	elapsed := float64(this.transition.elapsed) + float64(this.GetTickDuration())*this.GetInterpolationAlpha()
+	progress := math.Min(elapsed/float64(this.transition.transition.GetDuration()), 1)
+
+	from := this.transition.from
+	this.transition.transition.Render(this, progress, func(alpha float64) {
+		this.renderSceneList(from, alpha)
+	}, func(alpha float64) {
+		this.renderSceneList(visible, alpha)
+	})
+}
+
+// renderSceneList renders the given scenes with their opacity multiplied by
+// the given alpha.
+func (this *Engine) renderSceneList(scenes []*Scene, alpha float64) {
+	for _, scene := range scenes {
+		scene.performRender(this, alpha)
+	}
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,46 @@
+package go2d
+
+import (
+	"testing"
+	"time"
+)
+
+// fixedTransition renders the scenes it transitions to with a fixed alpha.
+type fixedTransition struct {
+	alpha float64
+}
+
+func (this fixedTransition) GetDuration() time.Duration {
+	return time.Second
+}
+
+func (this fixedTransition) Render(engine *Engine, progress float64, renderFrom func(alpha float64), renderTo func(alpha float64)) {
+	renderTo(this.alpha)
+}
+
+func TestTransitionsFadeScenesWithoutChangingThem(t *testing.T) {
+	for _, test := range []struct {
+		alpha float64
+		red   uint8
+	}{
+		{0, 0},
+		{0.5, 128},
+		{1, 255},
+	} {
+		engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+		defer engine.Stop()
+
+		scene := NewScene(engine, "faded")
+		scene.AddEntity(0, &squareEntity{Entity{Bounds: NewRect(16, 16, 32, 32), Alpha: 1}})
+		engine.PushScene(&scene, fixedTransition{alpha: test.alpha})
+		engine.RenderFrame()
+
+		pixel := engine.GetFrame().RGBAAt(32, 32)
+		if diff := int(pixel.R) - int(test.red); diff < -2 || diff > 2 {
+			t.Errorf("alpha %v: red = %v, want %v", test.alpha, pixel.R, test.red)
+		}
+		if scene.Alpha != 1 || scene.GetEntityGroupEntity().WorldAlpha() != 1 {
+			t.Errorf("alpha %v: rendering the transition changed the scene", test.alpha)
+		}
+	}
+}
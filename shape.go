@@ -0,0 +1,418 @@
+package go2d
+
+import (
+	"math"
+)
+
+// IShape is a convex shape that can be tested for collisions with the
+// Separating Axis Theorem. A shape is described by the convex hull of its
+// vertices, expanded outwards by its radius. A circle is a single vertex with
+// a radius, a capsule is two vertices with a radius and a polygon is any
+// number of vertices without a radius.
+type IShape interface {
+	// GetBounds returns the smallest axis aligned rectangle that contains
+	// the shape.
+	GetBounds() Rect
+	// GetVertices returns the vertices of the shape in order around its
+	// outline.
+	GetVertices() []Vector
+	// GetRadius returns the distance the shape extends past its vertices.
+	GetRadius() float64
+}
+
+// ShapeContact describes how two shapes overlap.
+type ShapeContact struct {
+	// Normal is the unit vector pointing from the first shape towards the
+	// second shape along which they overlap the least.
+	Normal Vector
+	// Depth is how far the shapes overlap along the normal. Moving the
+	// second shape by Normal * Depth separates the shapes.
+	Depth float64
+}
+
+// Circle is a circle shape.
+type Circle struct {
+	Center Vector
+	Radius float64
+}
+
+// NewCircle creates a new circle with the given center and radius.
+func NewCircle(center Vector, radius float64) Circle {
+	return Circle{
+		Center: center,
+		Radius: radius,
+	}
+}
+
+// GetBounds returns the smallest rectangle that contains the circle.
+func (this Circle) GetBounds() Rect {
+	return shapeBounds(this)
+}
+
+// GetVertices returns the center of the circle.
+func (this Circle) GetVertices() []Vector {
+	return []Vector{this.Center}
+}
+
+// GetRadius returns the radius of the circle.
+func (this Circle) GetRadius() float64 {
+	return this.Radius
+}
+
+// Capsule is a shape made of every point within a radius of the line
+// segment between A and B.
+type Capsule struct {
+	A      Vector
+	B      Vector
+	Radius float64
+}
+
+// NewCapsule creates a new capsule around the line segment between a and b.
+func NewCapsule(a Vector, b Vector, radius float64) Capsule {
+	return Capsule{
+		A:      a,
+		B:      b,
+		Radius: radius,
+	}
+}
+
+// GetBounds returns the smallest rectangle that contains the capsule.
+func (this Capsule) GetBounds() Rect {
+	return shapeBounds(this)
+}
+
+// GetVertices returns the ends of the line segment of the capsule.
+func (this Capsule) GetVertices() []Vector {
+	return []Vector{this.A, this.B}
+}
+
+// GetRadius returns the radius of the capsule.
+func (this Capsule) GetRadius() float64 {
+	return this.Radius
+}
+
+// Polygon is a convex polygon shape.
+type Polygon struct {
+	// Points are the vertices of the polygon in order around its outline.
+	Points []Vector
+}
+
+// NewPolygon creates a new convex polygon from the given points, which must
+// be in order around its outline.
+func NewPolygon(points ...Vector) Polygon {
+	return Polygon{
+		Points: points,
+	}
+}
+
+// GetBounds returns the smallest rectangle that contains the polygon.
+func (this Polygon) GetBounds() Rect {
+	return shapeBounds(this)
+}
+
+// GetVertices returns the points of the polygon.
+func (this Polygon) GetVertices() []Vector {
+	return this.Points
+}
+
+// GetRadius returns zero.
+func (this Polygon) GetRadius() float64 {
+	return 0
+}
+
+// OrientedRect is a rectangle that is rotated around its center.
+type OrientedRect struct {
+	Dimensions
+
+	// Center is the center of the rectangle.
+	Center Vector
+	// Rotation is the clockwise rotation of the rectangle in radians.
+	Rotation float64
+}
+
+// NewOrientedRect creates a new rectangle with the given center and size,
+// rotated clockwise by the given angle in radians.
+func NewOrientedRect(center Vector, size Dimensions, rotation float64) OrientedRect {
+	return OrientedRect{
+		Dimensions: size,
+		Center:     center,
+		Rotation:   rotation,
+	}
+}
+
+// GetBounds returns the smallest axis aligned rectangle that contains the
+// rectangle.
+func (this OrientedRect) GetBounds() Rect {
+	return shapeBounds(this)
+}
+
+// GetVertices returns the corners of the rectangle.
+func (this OrientedRect) GetVertices() []Vector {
+	w, h := this.Width/2, this.Height/2
+	corners := []Vector{{X: -w, Y: -h}, {X: w, Y: -h}, {X: w, Y: h}, {X: -w, Y: h}}
+	for i, corner := range corners {
+		corners[i] = corner.Rotated(this.Rotation).Add(this.Center)
+	}
+
+	return corners
+}
+
+// GetRadius returns zero.
+func (this OrientedRect) GetRadius() float64 {
+	return 0
+}
+
+// GetBounds returns the rectangle, so that rectangles can be used as shapes.
+func (this Rect) GetBounds() Rect {
+	return this
+}
+
+// GetVertices returns the corners of the rectangle.
+func (this Rect) GetVertices() []Vector {
+	return []Vector{
+		{X: this.X, Y: this.Y},
+		{X: this.X + this.Width, Y: this.Y},
+		{X: this.X + this.Width, Y: this.Y + this.Height},
+		{X: this.X, Y: this.Y + this.Height},
+	}
+}
+
+// GetRadius returns zero.
+func (this Rect) GetRadius() float64 {
+	return 0
+}
+
+// CollideShapes tests whether two shapes overlap using the Separating Axis
+// Theorem. If they do, the contact describing the smallest overlap is
+// returned. Shapes that only touch do not overlap.
+func CollideShapes(a IShape, b IShape) (ShapeContact, bool) {
+	va, ra := a.GetVertices(), a.GetRadius()
+	vb, rb := b.GetVertices(), b.GetRadius()
+	if len(va) == 0 || len(vb) == 0 {
+		return ShapeContact{}, false
+	}
+
+	axes := append(edgeNormals(va), edgeNormals(vb)...)
+	if ra > 0 || rb > 0 {
+		axes = append(axes, closestAxes(va, vb)...)
+		axes = append(axes, closestAxes(vb, va)...)
+	}
+	if len(axes) == 0 {
+		axes = append(axes, DirectionRight())
+	}
+
+	contact := ShapeContact{Depth: math.Inf(1)}
+	for _, axis := range axes {
+		minA, maxA := projectVertices(va, axis)
+		minB, maxB := projectVertices(vb, axis)
+		minA, maxA = minA-ra, maxA+ra
+		minB, maxB = minB-rb, maxB+rb
+
+		forward, backward := maxA-minB, maxB-minA
+		if forward <= 0 || backward <= 0 {
+			return ShapeContact{}, false
+		}
+
+		if forward < contact.Depth {
+			contact = ShapeContact{Normal: axis, Depth: forward}
+		}
+		if backward < contact.Depth {
+			contact = ShapeContact{Normal: axis.Inverted(), Depth: backward}
+		}
+	}
+
+	return contact, true
+}
+
+// ShapesIntersect returns true if the two shapes overlap.
+func ShapesIntersect(a IShape, b IShape) bool {
+	_, intersects := CollideShapes(a, b)
+	return intersects
+}
+
+// shapeBounds returns the smallest rectangle containing the given shape.
+func shapeBounds(shape IShape) Rect {
+	vertices := shape.GetVertices()
+	if len(vertices) == 0 {
+		return Rect{}
+	}
+
+	min, max := vertices[0], vertices[0]
+	for _, v := range vertices[1:] {
+		min = Vector{X: math.Min(min.X, v.X), Y: math.Min(min.Y, v.Y)}
+		max = Vector{X: math.Max(max.X, v.X), Y: math.Max(max.Y, v.Y)}
+	}
+
+	r := shape.GetRadius()
+	return NewRect(min.X-r, min.Y-r, max.X-min.X+r*2, max.Y-min.Y+r*2)
+}
+
+// edgeNormals returns the unit normals of the edges between the vertices.
+func edgeNormals(vertices []Vector) []Vector {
+	count := len(vertices)
+	if count == 2 {
+		// A line segment only has one edge.
+		count = 1
+	}
+
+	normals := []Vector{}
+	for i := 0; i < count && len(vertices) > 1; i++ {
+		edge := vertices[(i+1)%len(vertices)].Sub(vertices[i])
+		if edge.Length() > 0 {
+			normals = append(normals, edge.Perpendicular().Normalized())
+		}
+	}
+
+	return normals
+}
+
+// closestAxes returns the unit vectors from each of the given vertices to the
+// closest point on the outline of the other vertices. These are the axes that
+// can separate rounded shapes near their corners.
+func closestAxes(vertices []Vector, other []Vector) []Vector {
+	axes := []Vector{}
+	for _, v := range vertices {
+		axis := closestPointOnOutline(other, v).Sub(v)
+		if axis.Length() > 0 {
+			axes = append(axes, axis.Normalized())
+		}
+	}
+
+	return axes
+}
+
+// closestPointOnOutline returns the closest point to p on the outline formed
+// by the given vertices.
+func closestPointOnOutline(vertices []Vector, p Vector) Vector {
+	if len(vertices) == 1 {
+		return vertices[0]
+	}
+
+	closest := vertices[0]
+	distance := math.Inf(1)
+	for i := range vertices {
+		point := closestPointOnSegment(vertices[i], vertices[(i+1)%len(vertices)], p)
+		if d := point.Sub(p).Length(); d < distance {
+			closest, distance = point, d
+		}
+	}
+
+	return closest
+}
+
+// closestPointOnSegment returns the closest point to p on the line segment
+// between a and b.
+func closestPointOnSegment(a Vector, b Vector, p Vector) Vector {
+	ab := b.Sub(a)
+	length := ab.Dot(ab)
+	if length == 0 {
+		return a
+	}
+
+	t := math.Max(0, math.Min(1, p.Sub(a).Dot(ab)/length))
+	return a.Add(ab.Scaled(t))
+}
+
+// projectVertices returns the range the vertices cover along the axis.
+func projectVertices(vertices []Vector, axis Vector) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range vertices {
+		d := v.Dot(axis)
+		min, max = math.Min(min, d), math.Max(max, d)
+	}
+
+	return min, max
+}
+
+// translatedShape is a shape moved by an offset.
+type translatedShape struct {
+	shape  IShape
+	offset Vector
+}
+
+func (this translatedShape) GetBounds() Rect {
+	return shapeBounds(this)
+}
+
+func (this translatedShape) GetVertices() []Vector {
+	vertices := []Vector{}
+	for _, v := range this.shape.GetVertices() {
+		vertices = append(vertices, v.Add(this.offset))
+	}
+
+	return vertices
+}
+
+func (this translatedShape) GetRadius() float64 {
+	return this.shape.GetRadius()
+}
+
+// transformedShape is a shape transformed by a transform.
+type transformedShape struct {
+	shape     IShape
+	transform Transform
+}
+
+func (this transformedShape) GetBounds() Rect {
+	return shapeBounds(this)
+}
+
+func (this transformedShape) GetVertices() []Vector {
+	vertices := []Vector{}
+	for _, v := range this.shape.GetVertices() {
+		vertices = append(vertices, this.transform.Apply(v))
+	}
+
+	return vertices
+}
+
+// GetRadius returns the radius of the shape scaled by the average scale of
+// the transform, since a rounded shape can only be scaled uniformly.
+func (this transformedShape) GetRadius() float64 {
+	t := this.transform
+	return this.shape.GetRadius() * math.Sqrt(math.Abs(t.A*t.D-t.B*t.C))
+}
+
+// transformShape returns a copy of the shape transformed by the given
+// transform.
+func transformShape(shape IShape, t Transform) IShape {
+	if t.A == 1 && t.B == 0 && t.C == 0 && t.D == 1 {
+		if t.E == 0 && t.F == 0 {
+			return shape
+		}
+
+		return translateShape(shape, Vector{X: t.E, Y: t.F})
+	}
+
+	return transformedShape{shape: shape, transform: t}
+}
+
+// translateShape returns a copy of the shape moved by the given offset.
+func translateShape(shape IShape, offset Vector) IShape {
+	switch s := shape.(type) {
+	case Rect:
+		s.Vector = s.Vector.Add(offset)
+		return s
+	case Circle:
+		s.Center = s.Center.Add(offset)
+		return s
+	case Capsule:
+		s.A, s.B = s.A.Add(offset), s.B.Add(offset)
+		return s
+	case OrientedRect:
+		s.Center = s.Center.Add(offset)
+		return s
+	}
+
+	return translatedShape{shape: shape, offset: offset}
+}
+
+// unionRects returns the smallest rectangle that contains both rectangles.
+func unionRects(a Rect, b Rect) Rect {
+	x, y := math.Min(a.X, b.X), math.Min(a.Y, b.Y)
+	return NewRect(
+		x, y,
+		math.Max(a.X+a.Width, b.X+b.Width)-x,
+		math.Max(a.Y+a.Height, b.Y+b.Height)-y,
+	)
+}
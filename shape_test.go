@@ -0,0 +1,117 @@
+package go2d
+
+import (
+	"math"
+	"testing"
+)
+
+const shapeTolerance = 1e-9
+
+func vectorsClose(a Vector, b Vector) bool {
+	return math.Abs(a.X-b.X) < shapeTolerance && math.Abs(a.Y-b.Y) < shapeTolerance
+}
+
+func TestMinimumTranslation(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b       Rect
+		wantMTV    Vector
+		wantSide   RectSide
+		intersects bool
+	}{
+		{"overlapping from the left", NewRect(0, 0, 10, 10), NewRect(8, 2, 10, 10), Vector{X: -2}, RectSideRight, true},
+		{"overlapping from the right", NewRect(8, 2, 10, 10), NewRect(0, 0, 10, 10), Vector{X: 2}, RectSideLeft, true},
+		{"overlapping from above", NewRect(2, 0, 10, 10), NewRect(0, 7, 10, 10), Vector{Y: -3}, RectSideBottom, true},
+		{"overlapping from below", NewRect(0, 7, 10, 10), NewRect(2, 0, 10, 10), Vector{Y: 3}, RectSideTop, true},
+		{"contained", NewRect(1, 4, 2, 2), NewRect(0, 0, 10, 10), Vector{X: -3}, RectSideRight, true},
+		{"containing", NewRect(0, 0, 10, 10), NewRect(4, 1, 2, 2), Vector{Y: 3}, RectSideTop, true},
+		{"zero size inside", NewRect(8, 5, 0, 0), NewRect(0, 0, 10, 10), Vector{X: 2}, RectSideLeft, true},
+		{"touching", NewRect(0, 0, 10, 10), NewRect(10, 0, 10, 10), Vector{}, RectSideLeft, false},
+		{"touching corners", NewRect(0, 0, 10, 10), NewRect(10, 10, 10, 10), Vector{}, RectSideLeft, false},
+		{"separated", NewRect(0, 0, 10, 10), NewRect(20, 20, 10, 10), Vector{}, RectSideLeft, false},
+		{"zero size on an edge", NewRect(0, 5, 0, 0), NewRect(0, 0, 10, 10), Vector{}, RectSideLeft, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mtv, side, intersects := test.a.MinimumTranslation(test.b)
+			if intersects != test.intersects {
+				t.Fatalf("intersects = %v, want %v", intersects, test.intersects)
+			}
+			if !vectorsClose(mtv, test.wantMTV) || side != test.wantSide {
+				t.Errorf("got %v on side %v, want %v on side %v", mtv, side, test.wantMTV, test.wantSide)
+			}
+
+			if intersects {
+				moved := test.a
+				moved.Vector = moved.Vector.Add(mtv)
+				if moved.IntersectsWith(test.b) {
+					t.Errorf("%v still intersects %v after moving by %v", moved, test.b, mtv)
+				}
+			}
+		})
+	}
+}
+
+func TestCollideShapes(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b       IShape
+		intersects bool
+		depth      float64
+		// normal is only checked when it is not zero, since shapes that are
+		// contained in each other can be separated equally well along
+		// several axes.
+		normal Vector
+	}{
+		{"overlapping rects", NewRect(0, 0, 10, 10), NewRect(8, 0, 10, 10), true, 2, Vector{X: 1}},
+		{"touching rects", NewRect(0, 0, 10, 10), NewRect(10, 0, 10, 10), false, 0, Vector{}},
+		{"separated rects", NewRect(0, 0, 10, 10), NewRect(0, 30, 10, 10), false, 0, Vector{}},
+		{"contained rect", NewRect(4, 4, 2, 2), NewRect(0, 0, 10, 10), true, 6, Vector{}},
+		{"overlapping circles", NewCircle(Vector{}, 5), NewCircle(Vector{X: 8}, 5), true, 2, Vector{X: 1}},
+		{"touching circles", NewCircle(Vector{}, 5), NewCircle(Vector{X: 10}, 5), false, 0, Vector{}},
+		{"separated circles", NewCircle(Vector{}, 5), NewCircle(Vector{Y: -20}, 5), false, 0, Vector{}},
+		{"concentric circles", NewCircle(Vector{X: 3, Y: 3}, 5), NewCircle(Vector{X: 3, Y: 3}, 2), true, 7, Vector{}},
+		{"circle overlapping a rect", NewCircle(Vector{X: 5, Y: -4}, 5), NewRect(0, 0, 10, 10), true, 1, Vector{Y: 1}},
+		{"circle near a rect corner", NewCircle(Vector{X: -4, Y: -4}, 5), NewRect(0, 0, 10, 10), false, 0, Vector{}},
+		{"circle contained in a rect", NewCircle(Vector{X: 5, Y: 5}, 1), NewRect(0, 0, 10, 10), true, 6, Vector{}},
+		{"zero radius circle in a rect", NewCircle(Vector{X: 2, Y: 5}, 0), NewRect(0, 0, 10, 10), true, 2, Vector{X: 1}},
+		{"zero radius circle on a rect edge", NewCircle(Vector{X: 0, Y: 5}, 0), NewRect(0, 0, 10, 10), false, 0, Vector{}},
+		{"zero radius circles", NewCircle(Vector{X: 1, Y: 1}, 0), NewCircle(Vector{X: 1, Y: 1}, 0), false, 0, Vector{}},
+		{"capsule overlapping a rect", NewCapsule(Vector{Y: -10}, Vector{Y: 10}, 2), NewRect(1, -5, 10, 10), true, 1, Vector{X: 1}},
+		{"capsule past the end of a rect", NewCapsule(Vector{X: -20}, Vector{X: -3}, 2), NewRect(0, 0, 10, 10), false, 0, Vector{}},
+		{"zero length capsule", NewCapsule(Vector{X: 5, Y: 12}, Vector{X: 5, Y: 12}, 3), NewRect(0, 0, 10, 10), true, 1, Vector{Y: -1}},
+		{"rotated rect overlapping a rect", NewOrientedRect(Vector{}, Dimensions{Width: 10, Height: 10}, math.Pi/4), NewRect(6, -1, 10, 2), true, 5*math.Sqrt2 - 6, Vector{X: 1}},
+		{"rotated rect near a rect corner", NewOrientedRect(Vector{}, Dimensions{Width: 10, Height: 10}, math.Pi/4), NewRect(4, 4, 10, 10), false, 0, Vector{}},
+		{"triangle overlapping a circle", NewPolygon(Vector{}, Vector{X: 10}, Vector{Y: 10}), NewCircle(Vector{X: -1, Y: 5}, 2), true, 1, Vector{X: -1}},
+		{"empty polygon", NewPolygon(), NewRect(0, 0, 10, 10), false, 0, Vector{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contact, intersects := CollideShapes(test.a, test.b)
+			if intersects != test.intersects {
+				t.Fatalf("intersects = %v, want %v", intersects, test.intersects)
+			}
+			if ShapesIntersect(test.a, test.b) != intersects {
+				t.Errorf("ShapesIntersect disagrees with CollideShapes")
+			}
+			if !intersects {
+				return
+			}
+
+			if math.Abs(contact.Depth-test.depth) > shapeTolerance {
+				t.Errorf("depth = %v, want %v", contact.Depth, test.depth)
+			}
+			if !test.normal.IsZero() && !vectorsClose(contact.Normal, test.normal) {
+				t.Errorf("normal = %v, want %v", contact.Normal, test.normal)
+			}
+
+			// Moving the second shape along the contact separates them.
+			separated := translateShape(test.b, contact.Normal.Scaled(contact.Depth+shapeTolerance))
+			if ShapesIntersect(test.a, separated) {
+				t.Errorf("shapes still intersect after moving by %v", contact.Normal.Scaled(contact.Depth))
+			}
+		})
+	}
+}
@@ -23,6 +23,9 @@ const BULLET_SIZE = 32
 const BULLET_SPEED = 20
 const BULLET_LAYER = 0
 
+var ENEMY_COLLISION_LAYER = go2d.NewCollisionLayer(1)
+var BULLET_COLLISION_LAYER = go2d.NewCollisionLayer(2)
+
 type Shooter struct {
 	*go2d.ImageEntity
 }
@@ -72,13 +75,30 @@ func (this *Projectile) Update(engine *go2d.Engine) {
 	}
 }
 
-func (this *Projectile) GetCollider() go2d.Rect {
-	return this.Bounds
+// Bullets are round, so collide with a circle instead of their bounds
+func (this *Projectile) GetShape() go2d.IShape {
+	return go2d.NewCircle(this.Bounds.Center(), BULLET_SIZE/2)
+}
+
+// Bullets move fast enough to skip over enemies between ticks, so sweep them
+func (this *Projectile) IsBullet() bool {
+	return true
+}
+
+// Bullets only collide with enemies, never with other bullets
+func (this *Projectile) GetCollisionLayer() go2d.CollisionLayer {
+	return BULLET_COLLISION_LAYER
+}
+
+func (this *Projectile) GetCollisionMask() go2d.CollisionLayer {
+	return ENEMY_COLLISION_LAYER
 }
 
 func (this *Projectile) CollidedWith(other interface{}) {
-	other.(*Enemy).Remove()
-	this.Remove()
+	if enemy, isEnemy := other.(*Enemy); isEnemy {
+		enemy.Remove()
+		this.Remove()
+	}
 }
 
 func (this *Projectile) Remove() {
@@ -101,9 +121,17 @@ func (this *Enemy) Update(engine *go2d.Engine) {
 	}
 }
 
-// Add a collider to enemies
-func (this *Enemy) GetCollider() go2d.Rect {
-	return this.Bounds
+// Add a circle collider to enemies
+func (this *Enemy) GetShape() go2d.IShape {
+	return go2d.NewCircle(this.Bounds.Center(), ENEMY_SIZE/2)
+}
+
+func (this *Enemy) GetCollisionLayer() go2d.CollisionLayer {
+	return ENEMY_COLLISION_LAYER
+}
+
+func (this *Enemy) GetCollisionMask() go2d.CollisionLayer {
+	return go2d.CollisionLayerAll
 }
 
 // Remove will remove the Enemy from it's owning scene.
@@ -161,14 +189,9 @@ func (this *ShooterScene) Initialize(engine *go2d.Engine, scene *go2d.Scene) {
 	scene.AddNamedEntity("player", 1, player)
 }
 
-func main() {
-	engine := go2d.NewEngine(
-		"Shooter",
-		go2d.NewAspectRatio(
-			16, 9, go2d.AspectRatioControlAxisWidth,
-		).NewDimensions(1200),
-	)
-
+// NewShooterScene creates the shooter scene for the given engine. It is kept
+// separate from main so that the game can also be driven by a headless engine.
+func NewShooterScene(engine *go2d.Engine) *go2d.Scene {
 	shooterScene := &ShooterScene{}
 	scene := go2d.NewScene(engine, "Level 1")
 	scene.Initializer = shooterScene
@@ -180,7 +203,18 @@ func main() {
 
 	scene.RenderStats("../test_resources/font.ttf", 24, "#ff0000")
 
-	engine.SetScene(&scene)
+	return &scene
+}
+
+func main() {
+	engine := go2d.NewEngine(
+		"Shooter",
+		go2d.NewAspectRatio(
+			16, 9, go2d.AspectRatioControlAxisWidth,
+		).NewDimensions(1200),
+	)
+
+	engine.SetScene(NewShooterScene(engine))
 	engine.HideCursor = true
 	engine.Run()
 }
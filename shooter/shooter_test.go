@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nathan-fiscaletti/go2d"
+)
+
+func newHeadlessShooter(t *testing.T) *go2d.Engine {
+	engine := go2d.NewHeadlessEngine(
+		"Shooter",
+		go2d.NewAspectRatio(
+			16, 9, go2d.AspectRatioControlAxisWidth,
+		).NewDimensions(1200),
+	)
+	t.Cleanup(engine.Stop)
+	t.Cleanup(func() {
+		activeEnemies = map[string]*Enemy{}
+	})
+
+	engine.SetScene(NewShooterScene(engine))
+	return engine
+}
+
+func TestShooterSpawnsEnemies(t *testing.T) {
+	engine := newHeadlessShooter(t)
+
+	// One second at 60 TPS triggers the spawner, which runs every 0.35
+	// seconds, twice.
+	for i := 0; i < 60; i++ {
+		engine.Step()
+	}
+
+	if len(activeEnemies) != 2 {
+		t.Fatalf("got %v enemies, want 2", len(activeEnemies))
+	}
+
+	for _, enemy := range activeEnemies {
+		if enemy.Bounds.Y <= -ENEMY_SIZE {
+			t.Errorf("enemy %v did not move down from %v", enemy.key, enemy.Bounds.Y)
+		}
+	}
+}
+
+func TestShooterBulletDestroysEnemy(t *testing.T) {
+	engine := newHeadlessShooter(t)
+	engine.GetScene().RemoveTimer("EnemySpawner")
+	engine.Step()
+
+	player := engine.GetScene().GetEntity(PLAYER_LAYER, "player").(*Shooter)
+	SpawnEnemy(engine)
+	var enemy *Enemy
+	for _, e := range activeEnemies {
+		enemy = e
+	}
+	enemy.MoveTo(go2d.Vector{
+		X: player.Bounds.Center().X - ENEMY_SIZE/2,
+		Y: 100,
+	})
+	enemy.Velocity = go2d.NewVelocityVector(0, 0, go2d.TICK_DURATION)
+
+	player.KeyUp(0, ' ', "Space")
+	for i := 0; i < 60 && len(activeEnemies) > 0; i++ {
+		engine.Step()
+	}
+
+	if len(activeEnemies) != 0 {
+		t.Fatalf("bullet did not destroy the enemy at %v", enemy.Bounds)
+	}
+	if engine.GetScene().GetEntity(ENEMY_LAYER, enemy.key) != nil {
+		t.Errorf("destroyed enemy is still in the scene")
+	}
+}
+
+func TestShooterPlayerStaysOnScreen(t *testing.T) {
+	engine := newHeadlessShooter(t)
+	player := engine.GetScene().GetEntity(PLAYER_LAYER, "player").(*Shooter)
+
+	player.KeyDown(0, 0, "ArrowLeft")
+	for i := 0; i < 30; i++ {
+		engine.Step()
+	}
+
+	// The player is constrained before it moves each tick, so it can end up
+	// at most one tick of movement past the edge of the screen.
+	if player.Bounds.X < -PLAYER_SPEED {
+		t.Errorf("player is at x = %v, want at least %v", player.Bounds.X, -PLAYER_SPEED)
+	}
+
+	player.KeyUp(0, 0, "ArrowLeft")
+	engine.Step()
+	engine.RenderFrame()
+	if r, g, b, _ := engine.GetFrame().At(int(player.Bounds.Center().X), int(player.Bounds.Center().Y)).RGBA(); r == 0 || g == 0 || b == 0 {
+		t.Errorf("player was not drawn at %v", player.Bounds)
+	}
+}
@@ -7,16 +7,20 @@ import (
 
 // SpriteSheet is a simple sprite sheet implementation.
 type SpriteSheet struct {
-	// RowSize is the size of each row in the sprite sheet.
+	// RowSize is the width of each sprite in the sprite sheet, which is the
+	// distance between sprites along a row.
 	RowSize int
-	// ColumnSize is the size of each column in the sprite sheet.
+	// ColumnSize is the height of each sprite in the sprite sheet, which is the
+	// distance between sprites along a column.
 	ColumnSize int
 
 	image image.Image
 }
 
 // NewSpriteSheet creates a new sprite sheet from the given image path with the
-// given column and row size.
+// given column and row size. The column size is the height of each sprite and
+// the row size is the width of each sprite. For packed sprite sheets with
+// sprites of different sizes, use a TextureAtlas instead.
 func NewSpriteSheet(path string, columnSize int, rowSize int) (*SpriteSheet, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -34,15 +38,59 @@ func NewSpriteSheet(path string, columnSize int, rowSize int) (*SpriteSheet, err
 	}, nil
 }
 
-// GetSprite returns the sprite at the given location.
+// GetSprite returns the sprite at the given location. It panics if the image of
+// the sprite sheet does not support sub images, use GetSpriteE to handle that
+// error.
 func (this *SpriteSheet) GetSprite(location Vector) image.Image {
-	return this.image.(interface {
+	sprite, err := this.GetSpriteE(location)
+	if err != nil {
+		panic(err)
+	}
+
+	return sprite
+}
+
+// GetSpriteE returns the sprite at the given location. If the image of the
+// sprite sheet does not support sub images, ErrSubImageUnsupported is
+// returned.
+func (this *SpriteSheet) GetSpriteE(location Vector) (image.Image, error) {
+	subImager, ok := this.image.(interface {
 		SubImage(r image.Rectangle) image.Image
-	}).SubImage(
+	})
+	if !ok {
+		return nil, ErrSubImageUnsupported
+	}
+
+	return subImager.SubImage(
 		image.Rect(
 			int(location.X*float64(this.RowSize)),
 			int(location.Y*float64(this.ColumnSize)),
 			int((location.X*float64(this.RowSize))+float64(this.RowSize)),
 			int((location.Y*float64(this.ColumnSize))+float64(this.ColumnSize))),
-	)
+	), nil
+}
+
+// GetSprites returns count consecutive sprites starting at the given location.
+// When the end of a row is reached, the sprites continue at the start of the
+// next row.
+func (this *SpriteSheet) GetSprites(start Vector, count int) ([]image.Image, error) {
+	columns := 1
+	if this.RowSize > 0 && this.image.Bounds().Dx() >= this.RowSize {
+		columns = this.image.Bounds().Dx() / this.RowSize
+	}
+
+	sprites := []image.Image{}
+	index := int(start.Y)*columns + int(start.X)
+	for i := 0; i < count; i++ {
+		sprite, err := this.GetSpriteE(Vector{
+			X: float64((index + i) % columns),
+			Y: float64((index + i) / columns),
+		})
+		if err != nil {
+			return nil, err
+		}
+		sprites = append(sprites, sprite)
+	}
+
+	return sprites, nil
 }
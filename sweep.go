@@ -0,0 +1,215 @@
+package go2d
+
+import (
+	"math"
+	"sort"
+)
+
+// SweepHit describes when a moving shape first touches another shape.
+type SweepHit struct {
+	// Time is the fraction, between 0 and 1, of the movement at which the
+	// shapes first touch.
+	Time float64
+	// Normal is the unit normal of the surface of the other shape that was
+	// hit, pointing back towards the moving shape.
+	Normal Vector
+}
+
+// IEntityBullet is an interface that can be implemented by fast moving
+// entities that implement IEntityCollider or IEntityShapeCollider. The
+// collision pass sweeps the collider of a bullet from where it was at the
+// start of the tick to where it is now, so that it cannot skip through thin
+// colliders.
+type IEntityBullet interface {
+	IsBullet() bool
+}
+
+// SweepRect returns when rectangle a, moving by the given movement, first
+// touches rectangle b. If they already overlap, the time of impact is zero.
+func SweepRect(a Rect, movement Vector, b Rect) (SweepHit, bool) {
+	if a.IntersectsWith(b) {
+		mtv, _, _ := a.MinimumTranslation(b)
+		return SweepHit{Normal: mtv.Normalized()}, true
+	}
+
+	enterX, exitX, normalX := sweepAxis(a.X, a.Width, movement.X, b.X, b.Width)
+	enterY, exitY, normalY := sweepAxis(a.Y, a.Height, movement.Y, b.Y, b.Height)
+
+	enter, exit := math.Max(enterX, enterY), math.Min(exitX, exitY)
+	if enter > exit || enter < 0 || enter > 1 {
+		return SweepHit{}, false
+	}
+
+	if enterX > enterY {
+		return SweepHit{Time: enter, Normal: Vector{X: normalX}}, true
+	}
+	return SweepHit{Time: enter, Normal: Vector{Y: normalY}}, true
+}
+
+// sweepAxis returns when a range starting at a with the given size and moving
+// by the given amount enters and exits the range starting at b, along with
+// the normal of the side of b it enters through.
+func sweepAxis(a, aSize, movement, b, bSize float64) (float64, float64, float64) {
+	if movement == 0 {
+		if a < b+bSize && a+aSize > b {
+			return math.Inf(-1), math.Inf(1), 0
+		}
+		return math.Inf(1), math.Inf(-1), 0
+	}
+
+	if movement > 0 {
+		return (b - (a + aSize)) / movement, (b + bSize - a) / movement, -1
+	}
+	return (b + bSize - a) / movement, (b - (a + aSize)) / movement, 1
+}
+
+// SweepCircle returns when circle a, moving by the given movement, first
+// touches shape b. If they already overlap, the time of impact is zero.
+func SweepCircle(a Circle, movement Vector, b IShape) (SweepHit, bool) {
+	return SweepShape(a, movement, b)
+}
+
+// SweepShape returns when shape a, moving by the given movement, first
+// touches shape b. If they already overlap, the time of impact is zero.
+func SweepShape(a IShape, movement Vector, b IShape) (SweepHit, bool) {
+	if rectA, isRect := a.(Rect); isRect {
+		if rectB, isOtherRect := b.(Rect); isOtherRect {
+			return SweepRect(rectA, movement, rectB)
+		}
+	}
+
+	if contact, intersects := CollideShapes(a, b); intersects {
+		return SweepHit{Normal: contact.Normal.Inverted()}, true
+	}
+
+	// Moving a towards b is the same as casting a ray from the origin
+	// towards the Minkowski difference of b and a.
+	vertices := []Vector{}
+	for _, vb := range b.GetVertices() {
+		for _, va := range a.GetVertices() {
+			vertices = append(vertices, vb.Sub(va))
+		}
+	}
+
+	return castRounded(Vector{}, movement, convexHull(vertices), a.GetRadius()+b.GetRadius())
+}
+
+// castRounded returns when a ray from origin moving by movement first touches
+// the convex polygon with the given vertices expanded by the given radius.
+func castRounded(origin Vector, movement Vector, vertices []Vector, radius float64) (SweepHit, bool) {
+	hit := SweepHit{Time: math.Inf(1)}
+
+	centroid := Vector{}
+	for _, v := range vertices {
+		centroid = centroid.Add(v.Scaled(1 / float64(len(vertices))))
+	}
+
+	for i := range vertices {
+		if len(vertices) < 2 || (len(vertices) == 2 && i == 1) {
+			break
+		}
+
+		p, q := vertices[i], vertices[(i+1)%len(vertices)]
+		normal := q.Sub(p).Perpendicular().Normalized()
+		normals := []Vector{normal}
+		if len(vertices) == 2 {
+			normals = append(normals, normal.Inverted())
+		} else if p.Sub(centroid).Dot(normal) < 0 {
+			normals[0] = normal.Inverted()
+		}
+
+		for _, n := range normals {
+			t, hits := castSegment(origin, movement, p.Add(n.Scaled(radius)), q.Add(n.Scaled(radius)), n)
+			if hits && t < hit.Time {
+				hit = SweepHit{Time: t, Normal: n}
+			}
+		}
+	}
+
+	if radius > 0 {
+		for _, v := range vertices {
+			t, hits := castCircle(origin, movement, v, radius)
+			if hits && t < hit.Time {
+				point := origin.Add(movement.Scaled(t))
+				hit = SweepHit{Time: t, Normal: point.Sub(v).Normalized()}
+			}
+		}
+	}
+
+	return hit, !math.IsInf(hit.Time, 1)
+}
+
+// castSegment returns when a ray crosses the segment between p and q from
+// the side that the normal points to.
+func castSegment(origin Vector, movement Vector, p Vector, q Vector, normal Vector) (float64, bool) {
+	approach := movement.Dot(normal)
+	if approach >= 0 {
+		return 0, false
+	}
+
+	t := p.Sub(origin).Dot(normal) / approach
+	if t < 0 || t > 1 {
+		return 0, false
+	}
+
+	edge := q.Sub(p)
+	along := origin.Add(movement.Scaled(t)).Sub(p).Dot(edge) / edge.Dot(edge)
+	return t, along >= 0 && along <= 1
+}
+
+// castCircle returns when a ray first touches the circle with the given
+// center and radius.
+func castCircle(origin Vector, movement Vector, center Vector, radius float64) (float64, bool) {
+	offset := origin.Sub(center)
+	a := movement.Dot(movement)
+	b := 2 * offset.Dot(movement)
+	c := offset.Dot(offset) - radius*radius
+
+	discriminant := b*b - 4*a*c
+	if a == 0 || discriminant < 0 {
+		return 0, false
+	}
+
+	t := (-b - math.Sqrt(discriminant)) / (2 * a)
+	return t, t >= 0 && t <= 1
+}
+
+// convexHull returns the convex hull of the points in order around its
+// outline.
+func convexHull(points []Vector) []Vector {
+	sorted := append([]Vector{}, points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X == sorted[j].X {
+			return sorted[i].Y < sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || !p.Equals(sorted[i-1]) {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	hull := []Vector{}
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range unique {
+			for len(hull) >= start+2 && hull[len(hull)-1].Sub(hull[len(hull)-2]).Cross(p.Sub(hull[len(hull)-2])) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1]
+
+		for i, j := 0, len(unique)-1; i < j; i, j = i+1, j-1 {
+			unique[i], unique[j] = unique[j], unique[i]
+		}
+	}
+
+	return hull
+}
@@ -0,0 +1,92 @@
+package go2d
+
+import (
+	"math"
+	"testing"
+)
+
+type sweepTest struct {
+	name     string
+	a        IShape
+	movement Vector
+	b        IShape
+	hits     bool
+	time     float64
+	normal   Vector
+}
+
+func checkSweep(t *testing.T, test sweepTest, hit SweepHit, hits bool) {
+	t.Helper()
+	if hits != test.hits {
+		t.Fatalf("hits = %v, want %v", hits, test.hits)
+	}
+	if !hits {
+		return
+	}
+
+	if math.Abs(hit.Time-test.time) > shapeTolerance {
+		t.Errorf("time = %v, want %v", hit.Time, test.time)
+	}
+	if !vectorsClose(hit.Normal, test.normal) {
+		t.Errorf("normal = %v, want %v", hit.Normal, test.normal)
+	}
+}
+
+func TestSweepRect(t *testing.T) {
+	tests := []sweepTest{
+		{"hitting a side", NewRect(0, 0, 10, 10), Vector{X: 20}, NewRect(20, 0, 10, 10), true, 0.5, Vector{X: -1}},
+		{"hitting from below", NewRect(0, 30, 10, 10), Vector{Y: -20}, NewRect(5, 0, 10, 10), true, 1, Vector{Y: 1}},
+		{"moving diagonally", NewRect(0, 0, 10, 10), Vector{X: 20, Y: 20}, NewRect(20, 15, 10, 10), true, 0.5, Vector{X: -1}},
+		{"passing through", NewRect(0, 0, 10, 10), Vector{X: 100}, NewRect(50, 0, 2, 10), true, 0.4, Vector{X: -1}},
+		{"stopping short", NewRect(0, 0, 10, 10), Vector{X: 5}, NewRect(20, 0, 10, 10), false, 0, Vector{}},
+		{"passing by", NewRect(0, 0, 10, 10), Vector{X: 100}, NewRect(50, 20, 10, 10), false, 0, Vector{}},
+		{"moving away", NewRect(0, 0, 10, 10), Vector{X: -20}, NewRect(20, 0, 10, 10), false, 0, Vector{}},
+		{"touching and moving in", NewRect(0, 0, 10, 10), Vector{X: 5}, NewRect(10, 0, 10, 10), true, 0, Vector{X: -1}},
+		{"touching and moving away", NewRect(0, 0, 10, 10), Vector{X: -5}, NewRect(10, 0, 10, 10), false, 0, Vector{}},
+		{"touching and sliding along", NewRect(0, 0, 10, 10), Vector{Y: 5}, NewRect(10, 0, 10, 10), false, 0, Vector{}},
+		{"already overlapping", NewRect(0, 0, 10, 10), Vector{X: 5}, NewRect(8, 0, 10, 10), true, 0, Vector{X: -1}},
+		{"contained", NewRect(4, 1, 2, 2), Vector{Y: 5}, NewRect(0, 0, 10, 10), true, 0, Vector{Y: -1}},
+		{"zero length sweep", NewRect(0, 0, 10, 10), Vector{}, NewRect(20, 0, 10, 10), false, 0, Vector{}},
+		{"zero length sweep overlapping", NewRect(0, 0, 10, 10), Vector{}, NewRect(8, 0, 10, 10), true, 0, Vector{X: -1}},
+		{"zero length sweep touching", NewRect(0, 0, 10, 10), Vector{}, NewRect(10, 0, 10, 10), false, 0, Vector{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hit, hits := SweepRect(test.a.(Rect), test.movement, test.b.(Rect))
+			checkSweep(t, test, hit, hits)
+
+			// SweepShape uses SweepRect for pairs of rectangles.
+			hit, hits = SweepShape(test.a, test.movement, test.b)
+			checkSweep(t, test, hit, hits)
+		})
+	}
+}
+
+func TestSweepShape(t *testing.T) {
+	tests := []sweepTest{
+		{"circle hitting a rect", NewCircle(Vector{Y: 5}, 2), Vector{X: 20}, NewRect(10, 0, 10, 10), true, 0.4, Vector{X: -1}},
+		{"circle hitting a circle", NewCircle(Vector{}, 1), Vector{X: 10}, NewCircle(Vector{X: 6}, 1), true, 0.4, Vector{X: -1}},
+		{"circle hitting a rect corner", NewCircle(Vector{}, 1), Vector{X: 10, Y: 10}, NewRect(5, 5, 5, 5), true, (5 - 1/math.Sqrt2) / 10, Vector{X: -1 / math.Sqrt2, Y: -1 / math.Sqrt2}},
+		{"circle passing through a thin wall", NewCircle(Vector{Y: 5}, 1), Vector{X: 100}, NewRect(50, 0, 1, 10), true, 0.49, Vector{X: -1}},
+		{"circle passing by a rect", NewCircle(Vector{}, 1), Vector{X: 10}, NewRect(5, 3, 5, 5), false, 0, Vector{}},
+		{"circle moving away", NewCircle(Vector{Y: 5}, 2), Vector{X: -20}, NewRect(10, 0, 10, 10), false, 0, Vector{}},
+		{"circle already overlapping", NewCircle(Vector{X: 9, Y: 5}, 2), Vector{X: 5}, NewRect(10, 0, 10, 10), true, 0, Vector{X: -1}},
+		{"rect hitting a circle", NewRect(0, 0, 10, 10), Vector{X: 20}, NewCircle(Vector{X: 20, Y: 5}, 5), true, 0.25, Vector{X: -1}},
+		{"capsule hitting a rect", NewCapsule(Vector{Y: 2}, Vector{Y: 8}, 1), Vector{X: 20}, NewRect(10, 0, 10, 10), true, 0.45, Vector{X: -1}},
+		{"triangle hitting a rect", NewPolygon(Vector{}, Vector{X: 5, Y: 5}, Vector{Y: 10}), Vector{X: 10}, NewRect(10, 0, 10, 10), true, 0.5, Vector{X: -1}},
+		{"rotated rect hitting a rect", NewOrientedRect(Vector{Y: 5}, Dimensions{Width: 2 * math.Sqrt2, Height: 2 * math.Sqrt2}, math.Pi/4), Vector{X: 20}, NewRect(10, 0, 10, 10), true, 0.4, Vector{X: -1}},
+		{"zero radius circle", NewCircle(Vector{Y: 5}, 0), Vector{X: 20}, NewRect(10, 0, 10, 10), true, 0.5, Vector{X: -1}},
+		{"zero radius circle touching and moving in", NewCircle(Vector{X: 10, Y: 5}, 0), Vector{X: 5}, NewRect(10, 0, 10, 10), true, 0, Vector{X: -1}},
+		{"zero radius circle touching and moving away", NewCircle(Vector{X: 10, Y: 5}, 0), Vector{X: -5}, NewRect(10, 0, 10, 10), false, 0, Vector{}},
+		{"zero length sweep", NewCircle(Vector{Y: 5}, 2), Vector{}, NewRect(10, 0, 10, 10), false, 0, Vector{}},
+		{"zero length sweep overlapping", NewCircle(Vector{X: 9, Y: 5}, 2), Vector{}, NewRect(10, 0, 10, 10), true, 0, Vector{X: -1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hit, hits := SweepShape(test.a, test.movement, test.b)
+			checkSweep(t, test, hit, hits)
+		})
+	}
+}
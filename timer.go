@@ -1,31 +1,161 @@
 package go2d
 
+import (
+	"time"
+)
+
+// TimerRepeatForever can be used as the Count of a timer to keep triggering
+// it until it is stopped.
+const TimerRepeatForever = 0
+
 type TimerTrigger interface {
 	OnTriggered(owner interface{})
 }
 
-// Timer is a simple timer that can be used to trigger events at a certain interval.
-type timer struct {
-	// Seconds is the interval in seconds at which the timer will trigger.
-	seconds float64
+// Timer is a simple timer that can be used to trigger events at a certain
+// interval. Timers measure time by the tick duration of the engine, so they
+// stay in sync with the rest of the game.
+type Timer struct {
+	// Interval is the time between each time the timer triggers.
+	Interval time.Duration
+	// Count is how many times the timer triggers before it stops, or
+	// TimerRepeatForever to keep triggering it.
+	Count int
+	// Trigger is notified each time the timer triggers, with the scene that
+	// the timer was added to as the owner.
+	Trigger TimerTrigger
+	// Callback is called each time the timer triggers.
+	Callback func()
 
-	currentTickInSecond int
-	trigger             TimerTrigger
+	elapsed   time.Duration
+	triggered int
+	paused    bool
+	stopped   bool
 }
 
 // NewTimer creates a new timer that will trigger the given TimerTrigger every given seconds.
-func NewTimer(seconds float64, trigger TimerTrigger) *timer {
-	return &timer{
-		seconds: seconds,
-		trigger: trigger,
+func NewTimer(seconds float64, trigger TimerTrigger) *Timer {
+	return &Timer{
+		Interval: secondsToDuration(seconds),
+		Trigger:  trigger,
+	}
+}
+
+// NewTimerFunc creates a new timer that will call the given function every
+// given seconds.
+func NewTimerFunc(seconds float64, callback func()) *Timer {
+	return &Timer{
+		Interval: secondsToDuration(seconds),
+		Callback: callback,
 	}
 }
 
-func (this *timer) notifyUpdate(owner interface{}, scene *Scene) {
-	if scene.engine.GetFPS() > 0 && (float64(this.currentTickInSecond)/float64(scene.engine.GetTPS())) > this.seconds {
-		this.trigger.OnTriggered(owner)
-		this.currentTickInSecond = 0
+// NewOneShotTimer creates a new timer that will call the given function once
+// after the given seconds.
+func NewOneShotTimer(seconds float64, callback func()) *Timer {
+	return &Timer{
+		Interval: secondsToDuration(seconds),
+		Count:    1,
+		Callback: callback,
 	}
+}
+
+// Pause stops the timer from counting down until it is resumed.
+func (this *Timer) Pause() {
+	this.paused = true
+}
+
+// Resume continues counting down a paused timer.
+func (this *Timer) Resume() {
+	this.paused = false
+}
+
+// IsPaused returns true if the timer is paused.
+func (this *Timer) IsPaused() bool {
+	return this.paused
+}
+
+// Stop cancels the timer so that it never triggers again. Stopped timers are
+// removed from their scene.
+func (this *Timer) Stop() {
+	this.stopped = true
+}
+
+// IsStopped returns true if the timer was stopped or has triggered as many
+// times as its count.
+func (this *Timer) IsStopped() bool {
+	return this.stopped
+}
+
+// Reset restarts the countdown to the next trigger and forgets how many times
+// the timer has triggered. Stopped timers have to be added to a scene again
+// after being reset.
+func (this *Timer) Reset() {
+	this.elapsed = 0
+	this.triggered = 0
+	this.stopped = false
+}
+
+// Remaining returns the time left until the timer triggers next.
+func (this *Timer) Remaining() time.Duration {
+	if this.stopped || this.elapsed >= this.Interval {
+		return 0
+	}
+
+	// The timer may have triggered slightly early, on the tick closest to
+	// when it was due.
+	if this.elapsed < 0 {
+		return this.Interval
+	}
+
+	return this.Interval - this.elapsed
+}
+
+// GetTriggerCount returns how many times the timer has triggered since it
+// was created or last reset.
+func (this *Timer) GetTriggerCount() int {
+	return this.triggered
+}
+
+// advance moves the timer forward by a tick of the given duration, triggering
+// it for each interval that has passed.
+func (this *Timer) advance(owner interface{}, d time.Duration) {
+	if this.paused || this.stopped {
+		return
+	}
+
+	// Tick durations are rounded down to the nanosecond, so an interval of a
+	// whole number of ticks would be reached a tick late. Instead, the timer
+	// triggers on the tick closest to when it is due, and the difference is
+	// carried over to the next interval so that it does not drift.
+	tolerance := d / 2
+	if this.Interval < d {
+		tolerance = this.Interval / 2
+	}
+
+	this.elapsed += d
+	for !this.stopped && !this.paused && this.elapsed+tolerance >= this.Interval {
+		this.elapsed -= this.Interval
+		this.triggered += 1
+		if this.Count != TimerRepeatForever && this.triggered >= this.Count {
+			this.stopped = true
+		}
+
+		if this.Trigger != nil {
+			this.Trigger.OnTriggered(owner)
+		}
+		if this.Callback != nil {
+			this.Callback()
+		}
+
+		// Timers without an interval trigger once per tick.
+		if this.Interval <= 0 {
+			this.elapsed = 0
+			break
+		}
+	}
+}
 
-	this.currentTickInSecond += 1
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
 }
@@ -0,0 +1,124 @@
+package go2d
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimersTriggerOnTheRightTick(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds float64
+		count   int
+		ticks   int
+		actions map[int]func(timer *Timer)
+		want    []int
+		stopped bool
+	}{
+		{"one shot", 1, 1, 120, nil, []int{60}, true},
+		{"repeating three times", 0.5, 3, 120, nil, []int{30, 60, 90}, true},
+		{"repeating forever", 0.25, TimerRepeatForever, 60, nil, []int{15, 30, 45, 60}, false},
+		{"interval of a third of a second", 1.0 / 3, TimerRepeatForever, 60, nil, []int{20, 40, 60}, false},
+		{
+			"paused and resumed", 1, 1, 120,
+			map[int]func(timer *Timer){
+				31: (*Timer).Pause,
+				61: (*Timer).Resume,
+			},
+			[]int{90}, true,
+		},
+		{
+			"reset", 0.5, 2, 120,
+			map[int]func(timer *Timer){
+				46: (*Timer).Reset,
+			},
+			[]int{30, 75, 105}, true,
+		},
+		{
+			"stopped", 1, TimerRepeatForever, 120,
+			map[int]func(timer *Timer){
+				31: (*Timer).Stop,
+			},
+			nil, true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine, scene := newTestScene()
+
+			tick := 0
+			triggered := []int{}
+			timer := NewTimerFunc(test.seconds, func() {
+				triggered = append(triggered, tick)
+			})
+			timer.Count = test.count
+			scene.AddTimer("timer", timer)
+
+			for tick = 1; tick <= test.ticks; tick++ {
+				if action, hasAction := test.actions[tick]; hasAction {
+					action(timer)
+				}
+				scene.performUpdate(engine)
+			}
+
+			if fmt.Sprint(triggered) != fmt.Sprint(test.want) {
+				t.Errorf("triggered on ticks %v, want %v", triggered, test.want)
+			}
+			if timer.IsStopped() != test.stopped {
+				t.Errorf("stopped = %v, want %v", timer.IsStopped(), test.stopped)
+			}
+			if (scene.GetTimer("timer") == nil) != test.stopped {
+				t.Errorf("the scene still has the timer = %v, want %v", scene.GetTimer("timer") != nil, !test.stopped)
+			}
+		})
+	}
+}
+
+func TestTimerIntervalShorterThanATick(t *testing.T) {
+	engine, scene := newTestScene()
+
+	triggered := 0
+	scene.AddTimer("timer", NewTimerFunc(1.0/240, func() {
+		triggered += 1
+	}))
+
+	updateScene(engine, scene, 60)
+	if triggered != 240 {
+		t.Errorf("triggered %v times in a second, want 240", triggered)
+	}
+}
+
+func TestTimerRemaining(t *testing.T) {
+	engine, scene := newTestScene()
+
+	timer := NewTimerFunc(1, func() {})
+	scene.AddTimer("timer", timer)
+
+	checks := []struct {
+		name   string
+		ticks  int
+		action func()
+		want   time.Duration
+	}{
+		{"when added", 0, nil, time.Second},
+		{"after a quarter", 15, nil, 3 * time.Second / 4},
+		{"while paused", 30, timer.Pause, 3 * time.Second / 4},
+		{"after resuming", 15, timer.Resume, time.Second / 2},
+		{"after triggering", 30, nil, time.Second},
+		{"after resetting", 0, timer.Reset, time.Second},
+		{"after stopping", 0, timer.Stop, 0},
+	}
+
+	for _, check := range checks {
+		if check.action != nil {
+			check.action()
+		}
+		updateScene(engine, scene, check.ticks)
+
+		if diff := timer.Remaining() - check.want; diff < -time.Microsecond || diff > time.Microsecond {
+			t.Errorf("%v: remaining = %v, want %v", check.name, timer.Remaining(), check.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package go2d
+
+import (
+	"math"
+)
+
+// Transform is a 2D affine transformation in the same form that the canvas
+// uses. A point is transformed as follows.
+//
+//	x' = A*x + C*y + E
+//	y' = B*x + D*y + F
+type Transform struct {
+	A, B, C, D, E, F float64
+}
+
+// NewIdentityTransform creates a transform that does not change anything.
+func NewIdentityTransform() Transform {
+	return Transform{A: 1, D: 1}
+}
+
+// NewTranslateTransform creates a transform that moves points by the given
+// offset.
+func NewTranslateTransform(offset Vector) Transform {
+	return Transform{A: 1, D: 1, E: offset.X, F: offset.Y}
+}
+
+// NewRotateTransform creates a transform that rotates points clockwise around
+// the origin by the given angle in radians.
+func NewRotateTransform(angle float64) Transform {
+	sin, cos := math.Sincos(angle)
+	return Transform{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// NewScaleTransform creates a transform that scales points away from the
+// origin by the given horizontal and vertical factors.
+func NewScaleTransform(scale Vector) Transform {
+	return Transform{A: scale.X, D: scale.Y}
+}
+
+// Then returns a transform that applies this transform followed by the other
+// transform.
+func (this Transform) Then(other Transform) Transform {
+	return Transform{
+		A: this.A*other.A + this.B*other.C,
+		B: this.A*other.B + this.B*other.D,
+		C: this.C*other.A + this.D*other.C,
+		D: this.C*other.B + this.D*other.D,
+		E: this.E*other.A + this.F*other.C + other.E,
+		F: this.E*other.B + this.F*other.D + other.F,
+	}
+}
+
+// Apply returns the given point transformed by this transform.
+func (this Transform) Apply(v Vector) Vector {
+	return Vector{
+		X: this.A*v.X + this.C*v.Y + this.E,
+		Y: this.B*v.X + this.D*v.Y + this.F,
+	}
+}
+
+// ApplyVector returns the given direction or offset transformed by this
+// transform, without moving it.
+func (this Transform) ApplyVector(v Vector) Vector {
+	return Vector{
+		X: this.A*v.X + this.C*v.Y,
+		Y: this.B*v.X + this.D*v.Y,
+	}
+}
+
+// ApplyRect returns the smallest axis aligned rectangle that contains the
+// given rectangle transformed by this transform.
+func (this Transform) ApplyRect(r Rect) Rect {
+	vertices := r.GetVertices()
+	for i, v := range vertices {
+		vertices[i] = this.Apply(v)
+	}
+
+	return NewPolygon(vertices...).GetBounds()
+}
+
+// Inverse returns the transform that undoes this transform. If this transform
+// cannot be undone, because it scales by zero, the identity transform is
+// returned.
+func (this Transform) Inverse() Transform {
+	determinant := this.A*this.D - this.B*this.C
+	if determinant == 0 {
+		return NewIdentityTransform()
+	}
+
+	return Transform{
+		A: this.D / determinant,
+		B: -this.B / determinant,
+		C: -this.C / determinant,
+		D: this.A / determinant,
+		E: (this.C*this.F - this.D*this.E) / determinant,
+		F: (this.B*this.E - this.A*this.F) / determinant,
+	}
+}
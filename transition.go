@@ -0,0 +1,195 @@
+package go2d
+
+import (
+	"time"
+)
+
+// ISceneTransition is an interface that can be implemented to draw the
+// transition between two sets of scenes when the scene stack of an engine
+// changes.
+type ISceneTransition interface {
+	// GetDuration returns how long the transition takes.
+	GetDuration() time.Duration
+	// Render draws the transition at the given progress, from 0 at the start
+	// of the transition to 1 at the end of it. renderFrom draws the scenes
+	// that were visible before the transition and renderTo draws the scenes
+	// that are visible after it, both with their opacity multiplied by the
+	// given alpha.
+	Render(engine *Engine, progress float64, renderFrom func(alpha float64), renderTo func(alpha float64))
+}
+
+// FadeTransition fades between scenes. Without a color the new scenes fade in
+// over the old scenes, otherwise the old scenes fade out to the color and the
+// new scenes fade in from it.
+type FadeTransition struct {
+	// Duration is how long the transition takes.
+	Duration time.Duration
+	// Color is the color to fade through, or an empty string to cross-fade.
+	Color string
+	// Easing is the easing curve of the transition. Transitions without an
+	// easing curve progress at a constant rate.
+	Easing EasingFunc
+}
+
+// NewFadeTransition creates a new transition that fades through the given
+// color over the given duration.
+func NewFadeTransition(duration time.Duration, color string) *FadeTransition {
+	return &FadeTransition{
+		Duration: duration,
+		Color:    color,
+	}
+}
+
+// NewCrossFadeTransition creates a new transition that fades the new scenes in
+// over the old scenes over the given duration.
+func NewCrossFadeTransition(duration time.Duration) *FadeTransition {
+	return &FadeTransition{
+		Duration: duration,
+	}
+}
+
+// GetDuration returns how long the transition takes.
+func (this *FadeTransition) GetDuration() time.Duration {
+	return this.Duration
+}
+
+// Render draws the transition at the given progress.
+func (this *FadeTransition) Render(engine *Engine, progress float64, renderFrom func(alpha float64), renderTo func(alpha float64)) {
+	progress = ease(this.Easing, progress)
+
+	if this.Color == "" {
+		renderFrom(1)
+		renderTo(progress)
+		return
+	}
+
+	// The color covers the old scenes during the first half of the
+	// transition and uncovers the new scenes during the second half.
+	coverage := progress * 2
+	if progress < 0.5 {
+		renderFrom(1)
+	} else {
+		renderTo(1)
+		coverage = 2 - coverage
+	}
+
+	engine.Canvas.Save()
+	engine.Canvas.SetGlobalAlpha(coverage)
+	engine.Canvas.SetFillStyle(this.Color)
+	engine.Canvas.FillRect(0, 0, engine.Dimensions.Width, engine.Dimensions.Height)
+	engine.Canvas.Restore()
+}
+
+// SlideTransition slides the new scenes in from one side of the screen while
+// the old scenes slide out the other side.
+type SlideTransition struct {
+	// Duration is how long the transition takes.
+	Duration time.Duration
+	// Direction is the direction that the scenes slide in, such as
+	// DirectionLeft() to slide the new scenes in from the right.
+	Direction Vector
+	// Easing is the easing curve of the transition. Transitions without an
+	// easing curve progress at a constant rate.
+	Easing EasingFunc
+}
+
+// NewSlideTransition creates a new transition that slides the scenes in the
+// given direction over the given duration.
+func NewSlideTransition(duration time.Duration, direction Vector) *SlideTransition {
+	return &SlideTransition{
+		Duration:  duration,
+		Direction: direction,
+	}
+}
+
+// GetDuration returns how long the transition takes.
+func (this *SlideTransition) GetDuration() time.Duration {
+	return this.Duration
+}
+
+// Render draws the transition at the given progress.
+func (this *SlideTransition) Render(engine *Engine, progress float64, renderFrom func(alpha float64), renderTo func(alpha float64)) {
+	progress = ease(this.Easing, progress)
+	screen := Vector{
+		X: this.Direction.X * engine.Dimensions.Width,
+		Y: this.Direction.Y * engine.Dimensions.Height,
+	}
+
+	from := screen.Scaled(progress)
+	engine.saveCanvas()
+	engine.transformCanvas(NewTranslateTransform(from))
+	renderFrom(1)
+	engine.restoreCanvas()
+
+	to := screen.Scaled(progress - 1)
+	engine.saveCanvas()
+	engine.transformCanvas(NewTranslateTransform(to))
+	renderTo(1)
+	engine.restoreCanvas()
+}
+
+// WipeTransition reveals the new scenes over the old scenes with an edge that
+// moves across the screen.
+type WipeTransition struct {
+	// Duration is how long the transition takes.
+	Duration time.Duration
+	// Direction is the direction that the edge moves in, such as
+	// DirectionRight() to reveal the new scenes from left to right.
+	Direction Vector
+	// Easing is the easing curve of the transition. Transitions without an
+	// easing curve progress at a constant rate.
+	Easing EasingFunc
+}
+
+// NewWipeTransition creates a new transition that reveals the new scenes in
+// the given direction over the given duration.
+func NewWipeTransition(duration time.Duration, direction Vector) *WipeTransition {
+	return &WipeTransition{
+		Duration:  duration,
+		Direction: direction,
+	}
+}
+
+// GetDuration returns how long the transition takes.
+func (this *WipeTransition) GetDuration() time.Duration {
+	return this.Duration
+}
+
+// Render draws the transition at the given progress.
+func (this *WipeTransition) Render(engine *Engine, progress float64, renderFrom func(alpha float64), renderTo func(alpha float64)) {
+	progress = ease(this.Easing, progress)
+	renderFrom(1)
+
+	// The revealed area grows from the side of the screen opposite to the
+	// direction of the wipe.
+	w, h := engine.Dimensions.Width, engine.Dimensions.Height
+	revealed := NewRect(0, 0, w, h)
+	if this.Direction.X != 0 {
+		revealed.Width = w * progress
+		if this.Direction.X < 0 {
+			revealed.X = w - revealed.Width
+		}
+	}
+	if this.Direction.Y != 0 {
+		revealed.Height = h * progress
+		if this.Direction.Y < 0 {
+			revealed.Y = h - revealed.Height
+		}
+	}
+
+	engine.Canvas.Save()
+	engine.Canvas.BeginPath()
+	engine.Canvas.Rect(revealed.X, revealed.Y, revealed.Width, revealed.Height)
+	engine.Canvas.Clip()
+	renderTo(1)
+	engine.Canvas.Restore()
+}
+
+// ease applies the given easing curve to the progress of a transition.
+func ease(easing EasingFunc, progress float64) float64 {
+	if easing == nil {
+		return progress
+	}
+
+	return easing(progress)
+}
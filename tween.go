@@ -0,0 +1,304 @@
+package go2d
+
+import (
+	"image/color"
+	"math"
+	"time"
+)
+
+// TweenRepeatForever can be used as the Repeat of a tween to repeat it until
+// it is removed.
+const TweenRepeatForever = -1
+
+// Tween animates a value from where it is when the tween starts to where it
+// should end up over a duration. Tweens are advanced by the TweenManager of a
+// scene on every tick.
+type Tween struct {
+	// Duration is how long it takes to play the tween once.
+	Duration time.Duration
+	// Delay is how long to wait before the tween starts.
+	Delay time.Duration
+	// Easing is the easing curve of the tween. Tweens without an easing curve
+	// progress at a constant rate.
+	Easing EasingFunc
+	// Repeat is how many more times the tween is played after it is played
+	// once, or TweenRepeatForever to keep playing it.
+	Repeat int
+	// Yoyo plays every other repetition of the tween backwards, so that the
+	// value moves back and forth instead of jumping back to the start.
+	Yoyo bool
+
+	// OnStart is called when the tween starts, after its delay.
+	OnStart func()
+	// OnRepeat is called each time the tween starts another repetition.
+	OnRepeat func()
+	// OnComplete is called when the tween has finished all of its
+	// repetitions. It is not called for tweens that are removed early.
+	OnComplete func()
+
+	start     func()
+	update    func(t float64)
+	next      []*Tween
+	elapsed   time.Duration
+	iteration int
+	started   bool
+	completed bool
+}
+
+// NewTween creates a new tween that calls update with the eased progress of
+// the tween, from 0 to 1, each time it is advanced.
+func NewTween(duration time.Duration, update func(t float64)) *Tween {
+	return &Tween{
+		Duration: duration,
+		update:   update,
+	}
+}
+
+// NewFloatTween creates a new tween that animates the float at target to the
+// given value.
+func NewFloatTween(target *float64, to float64, duration time.Duration) *Tween {
+	var from float64
+	tween := NewTween(duration, func(t float64) {
+		*target = from + (to-from)*t
+	})
+	tween.start = func() {
+		from = *target
+	}
+
+	return tween
+}
+
+// NewVectorTween creates a new tween that animates the vector at target to
+// the given vector. Use the Vector of the bounds of an entity as the target to
+// move the entity.
+func NewVectorTween(target *Vector, to Vector, duration time.Duration) *Tween {
+	var from Vector
+	tween := NewTween(duration, func(t float64) {
+		*target = from.Lerp(to, t)
+	})
+	tween.start = func() {
+		from = *target
+	}
+
+	return tween
+}
+
+// NewRectTween creates a new tween that animates the position and size of the
+// rectangle at target to those of the given rectangle.
+func NewRectTween(target *Rect, to Rect, duration time.Duration) *Tween {
+	var from Rect
+	tween := NewTween(duration, func(t float64) {
+		*target = Rect{
+			Vector: from.Vector.Lerp(to.Vector, t),
+			Dimensions: Dimensions{
+				Width:  from.Width + (to.Width-from.Width)*t,
+				Height: from.Height + (to.Height-from.Height)*t,
+			},
+		}
+	})
+	tween.start = func() {
+		from = *target
+	}
+
+	return tween
+}
+
+// NewColorTween creates a new tween that animates the color at target to the
+// given color. A nil color is treated as white, so that the Tint of an entity
+// can be used as the target.
+func NewColorTween(target *color.Color, to color.Color, duration time.Duration) *Tween {
+	var from color.Color
+	tween := NewTween(duration, func(t float64) {
+		fr, fg, fb, fa := unpremultipliedColor(whiteIfNil(from))
+		tr, tg, tb, ta := unpremultipliedColor(whiteIfNil(to))
+		*target = color.NRGBA{
+			R: lerpChannel(fr, tr, t),
+			G: lerpChannel(fg, tg, t),
+			B: lerpChannel(fb, tb, t),
+			A: lerpChannel(fa, ta, t),
+		}
+	})
+	tween.start = func() {
+		from = *target
+	}
+
+	return tween
+}
+
+// Then plays the next tween once this tween has completed, and returns the
+// next tween so that more tweens can be chained after it.
+func (this *Tween) Then(next *Tween) *Tween {
+	this.next = append(this.next, next)
+	return next
+}
+
+// IsCompleted returns true if the tween has finished all of its repetitions.
+func (this *Tween) IsCompleted() bool {
+	return this.completed
+}
+
+// Reset rewinds the tween so that it plays again from the start, including
+// its delay, the next time it is advanced.
+func (this *Tween) Reset() {
+	this.elapsed = 0
+	this.iteration = 0
+	this.started = false
+	this.completed = false
+}
+
+// advance moves the tween forward by the given amount of time and returns how
+// much of it was left over after the tween completed.
+func (this *Tween) advance(d time.Duration) time.Duration {
+	if this.completed {
+		return d
+	}
+
+	if !this.started {
+		if this.elapsed+d < this.Delay {
+			this.elapsed += d
+			return 0
+		}
+
+		d -= this.Delay - this.elapsed
+		this.elapsed = 0
+		this.started = true
+		if this.start != nil {
+			this.start()
+		}
+		if this.OnStart != nil {
+			this.OnStart()
+		}
+	}
+
+	this.elapsed += d
+	for this.Duration <= 0 || this.elapsed >= this.Duration {
+		if this.Duration <= 0 || (this.Repeat != TweenRepeatForever && this.iteration >= this.Repeat) {
+			this.apply(1)
+			this.completed = true
+			if this.OnComplete != nil {
+				this.OnComplete()
+			}
+
+			leftover := this.elapsed - this.Duration
+			if leftover < 0 {
+				leftover = 0
+			}
+			return leftover
+		}
+
+		this.elapsed -= this.Duration
+		this.iteration += 1
+		if this.OnRepeat != nil {
+			this.OnRepeat()
+		}
+	}
+
+	this.apply(float64(this.elapsed) / float64(this.Duration))
+	return 0
+}
+
+// apply updates the value of the tween for the given progress through the
+// current repetition.
+func (this *Tween) apply(progress float64) {
+	if this.Yoyo && this.iteration%2 == 1 {
+		progress = 1 - progress
+	}
+
+	easing := this.Easing
+	if easing == nil {
+		easing = EaseLinear
+	}
+
+	if this.update != nil {
+		this.update(easing(progress))
+	}
+}
+
+// TweenManager advances a set of tweens. Every scene has a TweenManager that
+// is advanced by the tick duration of the engine on every tick.
+type TweenManager struct {
+	tweens []*Tween
+}
+
+// NewTweenManager creates a new tween manager without any tweens.
+func NewTweenManager() *TweenManager {
+	return &TweenManager{}
+}
+
+// Add starts playing the given tween and returns it.
+func (this *TweenManager) Add(tween *Tween) *Tween {
+	this.tweens = append(this.tweens, tween)
+	return tween
+}
+
+// Remove stops playing the given tween. The tweens chained after it are not
+// played.
+func (this *TweenManager) Remove(tween *Tween) {
+	for i, t := range this.tweens {
+		if t == tween {
+			this.tweens = append(this.tweens[:i], this.tweens[i+1:]...)
+			return
+		}
+	}
+}
+
+// Clear stops playing all of the tweens.
+func (this *TweenManager) Clear() {
+	this.tweens = nil
+}
+
+// Count returns the number of tweens that are playing.
+func (this *TweenManager) Count() int {
+	return len(this.tweens)
+}
+
+// Advance moves all of the tweens forward by the given amount of time. When a
+// tween completes, the tweens chained after it start playing with the time
+// that was left over.
+func (this *TweenManager) Advance(d time.Duration) {
+	tweens := append([]*Tween{}, this.tweens...)
+	for _, tween := range tweens {
+		// Tweens can be removed by the callbacks of the tweens before them.
+		if this.contains(tween) {
+			this.play(tween, d)
+		}
+	}
+}
+
+// play advances the given tween and replaces it with the tweens chained after
+// it once it completes.
+func (this *TweenManager) play(tween *Tween, d time.Duration) {
+	leftover := tween.advance(d)
+	if !tween.completed {
+		return
+	}
+
+	this.Remove(tween)
+	for _, next := range tween.next {
+		next.Reset()
+		this.Add(next)
+		this.play(next, leftover)
+	}
+}
+
+func (this *TweenManager) contains(tween *Tween) bool {
+	for _, t := range this.tweens {
+		if t == tween {
+			return true
+		}
+	}
+
+	return false
+}
+
+func whiteIfNil(c color.Color) color.Color {
+	if c == nil {
+		return color.White
+	}
+
+	return c
+}
+
+func lerpChannel(from uint32, to uint32, t float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(float64(from)+(float64(to)-float64(from))*t))))
+}
@@ -0,0 +1,255 @@
+package go2d
+
+import (
+	"math"
+	"testing"
+)
+
+const tweenTolerance = 1e-9
+
+// newTestScene creates a scene on a headless engine that can be updated
+// without running the engine.
+func newTestScene() (*Engine, *Scene) {
+	engine := NewHeadlessEngine("test", Dimensions{Width: 64, Height: 64})
+	scene := NewScene(engine, "test")
+	return engine, &scene
+}
+
+// updateScene updates the scene for the given number of ticks.
+func updateScene(engine *Engine, scene *Scene, ticks int) {
+	for i := 0; i < ticks; i++ {
+		scene.performUpdate(engine)
+	}
+}
+
+func TestEasing(t *testing.T) {
+	easings := map[string]EasingFunc{
+		"linear":         EaseLinear,
+		"in quad":        EaseInQuad,
+		"out quad":       EaseOutQuad,
+		"in out quad":    EaseInOutQuad,
+		"in cubic":       EaseInCubic,
+		"out cubic":      EaseOutCubic,
+		"in out cubic":   EaseInOutCubic,
+		"in elastic":     EaseInElastic,
+		"out elastic":    EaseOutElastic,
+		"in out elastic": EaseInOutElastic,
+		"in bounce":      EaseInBounce,
+		"out bounce":     EaseOutBounce,
+		"in out bounce":  EaseInOutBounce,
+		"in back":        EaseInBack,
+		"out back":       EaseOutBack,
+		"in out back":    EaseInOutBack,
+	}
+
+	for name, easing := range easings {
+		if start, end := easing(0), easing(1); math.Abs(start) > tweenTolerance || math.Abs(end-1) > tweenTolerance {
+			t.Errorf("%v goes from %v to %v, want 0 to 1", name, start, end)
+		}
+	}
+
+	halfway := []struct {
+		name   string
+		easing EasingFunc
+		want   float64
+	}{
+		{"linear", EaseLinear, 0.5},
+		{"in quad", EaseInQuad, 0.25},
+		{"out quad", EaseOutQuad, 0.75},
+		{"in out quad", EaseInOutQuad, 0.5},
+		{"in cubic", EaseInCubic, 0.125},
+		{"out cubic", EaseOutCubic, 0.875},
+	}
+	for _, test := range halfway {
+		if got := test.easing(0.5); math.Abs(got-test.want) > tweenTolerance {
+			t.Errorf("%v is %v halfway, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTweenEasesTheValue(t *testing.T) {
+	engine, scene := newTestScene()
+	tick := engine.GetTickDuration()
+
+	value := 0.0
+	completed := 0
+	tween := NewFloatTween(&value, 10, 10*tick)
+	tween.Easing = EaseInQuad
+	tween.OnComplete = func() {
+		completed += 1
+	}
+	scene.Tweens.Add(tween)
+
+	updateScene(engine, scene, 5)
+	if math.Abs(value-2.5) > tweenTolerance || tween.IsCompleted() {
+		t.Fatalf("value is %v halfway, want 2.5", value)
+	}
+
+	updateScene(engine, scene, 5)
+	if value != 10 || !tween.IsCompleted() || completed != 1 {
+		t.Fatalf("value is %v after completing %v times, want 10 after completing once", value, completed)
+	}
+	if scene.Tweens.Count() != 0 {
+		t.Errorf("%v tweens are still playing, want 0", scene.Tweens.Count())
+	}
+
+	updateScene(engine, scene, 5)
+	if completed != 1 {
+		t.Errorf("completed %v times, want once", completed)
+	}
+}
+
+func TestTweenWaitsForItsDelay(t *testing.T) {
+	engine, scene := newTestScene()
+	tick := engine.GetTickDuration()
+
+	value := 0.0
+	started := 0
+	tween := NewFloatTween(&value, 10, 10*tick)
+	tween.Delay = 5 * tick
+	tween.OnStart = func() {
+		started += 1
+	}
+	scene.Tweens.Add(tween)
+
+	updateScene(engine, scene, 4)
+	if value != 0 || started != 0 {
+		t.Fatalf("value is %v and started %v times during the delay", value, started)
+	}
+
+	updateScene(engine, scene, 6)
+	if math.Abs(value-5) > tweenTolerance || started != 1 {
+		t.Fatalf("value is %v after starting %v times, want 5 after starting once", value, started)
+	}
+
+	updateScene(engine, scene, 5)
+	if value != 10 || !tween.IsCompleted() {
+		t.Errorf("value is %v after the delay and duration, want 10", value)
+	}
+}
+
+func TestTweenRepeats(t *testing.T) {
+	type checkpoint struct {
+		ticks int
+		value float64
+	}
+
+	tests := []struct {
+		name        string
+		repeat      int
+		yoyo        bool
+		checkpoints []checkpoint
+		repeated    int
+	}{
+		{"restarting", 2, false, []checkpoint{{3, 3}, {12, 2}, {22, 2}, {30, 10}}, 2},
+		{"yoyo", 2, true, []checkpoint{{3, 3}, {12, 8}, {22, 2}, {30, 10}}, 2},
+		{"yoyo ending backwards", 1, true, []checkpoint{{3, 3}, {12, 8}, {20, 0}}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine, scene := newTestScene()
+			tick := engine.GetTickDuration()
+
+			value := 0.0
+			repeated := 0
+			tween := NewFloatTween(&value, 10, 10*tick)
+			tween.Repeat = test.repeat
+			tween.Yoyo = test.yoyo
+			tween.OnRepeat = func() {
+				repeated += 1
+			}
+			scene.Tweens.Add(tween)
+
+			ticks := 0
+			for _, checkpoint := range test.checkpoints {
+				updateScene(engine, scene, checkpoint.ticks-ticks)
+				ticks = checkpoint.ticks
+				if math.Abs(value-checkpoint.value) > tweenTolerance {
+					t.Errorf("value is %v after %v ticks, want %v", value, ticks, checkpoint.value)
+				}
+			}
+
+			if !tween.IsCompleted() || repeated != test.repeated {
+				t.Errorf("completed = %v after repeating %v times, want to complete after repeating %v times", tween.IsCompleted(), repeated, test.repeated)
+			}
+		})
+	}
+}
+
+func TestTweenRepeatsForever(t *testing.T) {
+	engine, scene := newTestScene()
+	tick := engine.GetTickDuration()
+
+	value := 0.0
+	tween := scene.Tweens.Add(NewFloatTween(&value, 10, 10*tick))
+	tween.Repeat = TweenRepeatForever
+
+	updateScene(engine, scene, 1005)
+	if tween.IsCompleted() || scene.Tweens.Count() != 1 || math.Abs(value-5) > tweenTolerance {
+		t.Errorf("value is %v with %v tweens playing, want 5 with the tween still playing", value, scene.Tweens.Count())
+	}
+}
+
+func TestTweensChainedWithThen(t *testing.T) {
+	engine, scene := newTestScene()
+	tick := engine.GetTickDuration()
+
+	value := 0.0
+	events := []string{}
+	first := NewFloatTween(&value, 10, 10*tick)
+	first.OnComplete = func() {
+		events = append(events, "first completed")
+	}
+	second := NewFloatTween(&value, 0, 10*tick)
+	second.OnStart = func() {
+		events = append(events, "second started")
+	}
+	second.OnComplete = func() {
+		events = append(events, "second completed")
+	}
+	scene.Tweens.Add(first).Then(second)
+
+	updateScene(engine, scene, 10)
+	if value != 10 || !first.IsCompleted() || second.IsCompleted() {
+		t.Fatalf("value is %v after the first tween, want 10", value)
+	}
+
+	// The second tween starts from where the first one left the value.
+	updateScene(engine, scene, 5)
+	if math.Abs(value-5) > tweenTolerance {
+		t.Fatalf("value is %v halfway through the second tween, want 5", value)
+	}
+
+	updateScene(engine, scene, 5)
+	if value != 0 || !second.IsCompleted() || scene.Tweens.Count() != 0 {
+		t.Fatalf("value is %v with %v tweens playing, want 0 with none playing", value, scene.Tweens.Count())
+	}
+
+	want := []string{"first completed", "second started", "second completed"}
+	if len(events) != len(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("events = %v, want %v", events, want)
+		}
+	}
+}
+
+func TestTweenChainUsesLeftoverTime(t *testing.T) {
+	engine, scene := newTestScene()
+	tick := engine.GetTickDuration()
+
+	value := 0.0
+	first := NewFloatTween(&value, 10, tick*5/2)
+	second := NewFloatTween(&value, 20, 10*tick)
+	scene.Tweens.Add(first).Then(second)
+
+	// The first tween completes halfway through the third tick, so the
+	// second tween has already played for half a tick.
+	updateScene(engine, scene, 3)
+	if math.Abs(value-10.5) > 1e-6 {
+		t.Errorf("value is %v, want 10.5", value)
+	}
+}
@@ -35,20 +35,22 @@ func NewVelocityVector(x, y float64, d time.Duration) VelocityVector {
 // GetNextMovement returns the next movement that should be applied to the
 // vector. This is used to calculate the next movement of the vector based on
 // the duration. If the duration is 0, then the vector will move the given
-// amount in one tick.
+// amount in one tick. Otherwise the movement is that of a tick of an engine
+// running at 60 TPS. Use GetMovement for engines running at other rates.
 func (this VelocityVector) GetNextMovement() Vector {
+	return this.GetMovement(defaultTickDuration)
+}
+
+// GetMovement returns the movement that should be applied to the vector in a
+// tick of the given duration, so that it moves the full vector over its
+// duration. If the duration is 0, then the vector will move the given amount
+// in one tick.
+func (this VelocityVector) GetMovement(tickDuration time.Duration) Vector {
 	if this.Duration == TICK_DURATION {
 		return this.Vector
 	}
 
-	framesPerNanoSecond := 60 * time.Second
-	desiredXMovementPerNanoSecond := this.Vector.X / float64(this.Duration)
-	desiredYMovementPerNanoSecond := this.Vector.Y / float64(this.Duration)
-
-	return Vector{
-		X: float64(framesPerNanoSecond) / desiredXMovementPerNanoSecond,
-		Y: float64(framesPerNanoSecond) / desiredYMovementPerNanoSecond,
-	}
+	return this.Vector.Scaled(float64(tickDuration) / float64(this.Duration))
 }
 
 // Vector is a 2D vector with an X and Y component.
@@ -136,6 +138,84 @@ func (this *Vector) AngleTo(other Vector) float64 {
 	return math.Atan2(other.Y-this.Y, other.X-this.X)
 }
 
+// Lerp returns the vector that is the given fraction of the way from this
+// vector to the other vector.
+func (this Vector) Lerp(other Vector, t float64) Vector {
+	return Vector{
+		X: this.X + (other.X-this.X)*t,
+		Y: this.Y + (other.Y-this.Y)*t,
+	}
+}
+
+// Add returns the sum of this vector and the given vector.
+func (this Vector) Add(other Vector) Vector {
+	return Vector{
+		X: this.X + other.X,
+		Y: this.Y + other.Y,
+	}
+}
+
+// Sub returns the difference between this vector and the given vector.
+func (this Vector) Sub(other Vector) Vector {
+	return Vector{
+		X: this.X - other.X,
+		Y: this.Y - other.Y,
+	}
+}
+
+// Scaled returns a copy of this vector multiplied by the given factor.
+func (this Vector) Scaled(factor float64) Vector {
+	return Vector{
+		X: this.X * factor,
+		Y: this.Y * factor,
+	}
+}
+
+// Dot returns the dot product of this vector and the given vector.
+func (this Vector) Dot(other Vector) float64 {
+	return this.X*other.X + this.Y*other.Y
+}
+
+// Cross returns the z component of the cross product of this vector and the
+// given vector.
+func (this Vector) Cross(other Vector) float64 {
+	return this.X*other.Y - this.Y*other.X
+}
+
+// Length returns the length of this vector.
+func (this Vector) Length() float64 {
+	return math.Hypot(this.X, this.Y)
+}
+
+// Normalized returns a copy of this vector with a length of 1. The zero
+// vector is returned unchanged.
+func (this Vector) Normalized() Vector {
+	length := this.Length()
+	if length == 0 {
+		return this
+	}
+
+	return this.Scaled(1 / length)
+}
+
+// Perpendicular returns a copy of this vector rotated 90 degrees clockwise.
+func (this Vector) Perpendicular() Vector {
+	return Vector{
+		X: -this.Y,
+		Y: this.X,
+	}
+}
+
+// Rotated returns a copy of this vector rotated clockwise by the given angle
+// in radians around the origin.
+func (this Vector) Rotated(angle float64) Vector {
+	sin, cos := math.Sincos(angle)
+	return Vector{
+		X: this.X*cos - this.Y*sin,
+		Y: this.X*sin + this.Y*cos,
+	}
+}
+
 // ConstrainTo constrains this vector to the given rectangle. If the vector is
 // outside of the rectangle, it will be moved to the closest point on the
 // rectangle.
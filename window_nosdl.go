@@ -0,0 +1,13 @@
+//go:build nosdl
+
+package go2d
+
+import (
+	"github.com/tfriedel6/canvas"
+)
+
+// createWindow always fails, since SDL is not linked into programs that are
+// built with the nosdl build tag.
+func createWindow(engine *Engine) (engineWindow, *canvas.Canvas, error) {
+	return nil, nil, ErrWindowUnsupported
+}
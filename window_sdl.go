@@ -0,0 +1,82 @@
+//go:build !nosdl
+
+package go2d
+
+import (
+	"github.com/tfriedel6/canvas"
+	"github.com/tfriedel6/canvas/sdlcanvas"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// sdlWindow is an engine window that is created with SDL.
+type sdlWindow struct {
+	wnd *sdlcanvas.Window
+}
+
+// createWindow creates an SDL window for the given engine and forwards its
+// input events to the engine.
+func createWindow(engine *Engine) (engineWindow, *canvas.Canvas, error) {
+	sdl.SetHint(sdl.HINT_VIDEO_HIGHDPI_DISABLED, "1")
+	wnd, cv, err := sdlcanvas.CreateWindow(int(engine.Dimensions.Width), int(engine.Dimensions.Height), engine.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wnd.Window.SetResizable(false)
+
+	wnd.KeyDown = engine.keyDown
+	wnd.KeyUp = engine.keyUp
+	wnd.MouseUp = engine.mouseUp
+	wnd.MouseDown = engine.mouseDown
+	wnd.MouseMove = engine.mouseMove
+
+	wnd.SizeChange = func(w, h int) {
+		fbw, fbh := wnd.FramebufferSize()
+		wnd.Backend.SetBounds(0, 0, fbw, fbh)
+		engine.lock()
+		engine.resized()
+		engine.unlock()
+	}
+
+	return &sdlWindow{wnd: wnd}, cv, nil
+}
+
+func (this *sdlWindow) setTitle(title string) {
+	this.wnd.Window.SetTitle(title)
+}
+
+func (this *sdlWindow) setSize(size Dimensions) {
+	this.wnd.Window.SetSize(int32(size.Width), int32(size.Height))
+}
+
+func (this *sdlWindow) setResizable(resizable bool) {
+	this.wnd.Window.SetResizable(resizable)
+}
+
+func (this *sdlWindow) setFullscreen(fullscreen bool) error {
+	if fullscreen {
+		return this.wnd.Window.SetFullscreen(sdl.WINDOW_FULLSCREEN_DESKTOP)
+	}
+
+	return this.wnd.Window.SetFullscreen(0)
+}
+
+func (this *sdlWindow) setBorderless(borderless bool) {
+	this.wnd.Window.SetBordered(!borderless)
+}
+
+func (this *sdlWindow) hideCursor() {
+	sdl.ShowCursor(0)
+}
+
+func (this *sdlWindow) mainLoop(frame func()) {
+	this.wnd.MainLoop(frame)
+}
+
+func (this *sdlWindow) close() {
+	this.wnd.Close()
+}
+
+func (this *sdlWindow) destroy() {
+	this.wnd.Destroy()
+}